@@ -1,88 +1,189 @@
 package main
 
-/* Board is the top-level widget type describing a kanban board, which contains and manages stages */
+/* Board is the top-level widget type rendering a kanban board model, which contains and manages stage widgets */
 
 /* ================================================================================ Imports */
 import (
-	"encoding/json"
+	"bankan/model"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 )
 
 /* ================================================================================ Public types */
 type Board struct {
-	widget.BaseWidget `json:"-"`
-	Name              string
-	Stages            []*Stage
-	FilterTags        []Tag                      `json:"-"`
-	OnFilterChanged   func(tagEditString string) `json:"-"`
+	widget.BaseWidget
+	*model.Board
+	History         *model.History
+	Filter          *model.Filter
+	Search          *model.Search
+	SearchHit       *model.Item
+	FocusedStage    *model.Stage
+	FocusedItem     *model.Item
+	OnFilterChanged func(query string)
+	OnChanged       func()
+	stages          []*Stage
+	dragLayer       *dragLayer
 }
 
 /* ================================================================================ Private types */
 type boardRenderer struct {
 	stageContainer *fyne.Container
+	dragContainer  *fyne.Container
 	w              *Board
 }
 
 /* ================================================================================ Public functions */
-func NewBoard(name string, filterChanged func(tagEditString string)) *Board {
-	board := &Board{Name: name, OnFilterChanged: filterChanged}
+func NewBoard(name string, filterChanged func(query string)) *Board {
+	board := &Board{Board: model.NewBoard(name), History: model.NewHistory(), Filter: &model.Filter{}, OnFilterChanged: filterChanged, dragLayer: newDragLayer()}
 	board.ExtendBaseWidget(board)
 
 	return board
 }
 
-/* ================================================================================ Public methods */
-func (w *Board) Clear() {
-	w.Stages = w.Stages[:0]
+/* ================================================================================ Private methods */
+func (w *Board) stageWidget(stage *model.Stage) *Stage {
+	for _, existing := range w.stages {
+		if existing.Stage == stage {
+			return existing
+		}
+	}
+	return NewStage(w, stage)
+}
+
+// syncStages updates the stage widgets to match the stages of the board model, keeping existing widgets
+func (w *Board) syncStages() {
+	stages := make([]*Stage, len(w.Board.Stages))
+
+	for i, stage := range w.Board.Stages {
+		stages[i] = w.stageWidget(stage)
+	}
+	w.stages = stages
+}
+
+func (w *Board) loaded() {
+	w.History.Clear()
 	w.Refresh()
+
+	if w.OnChanged != nil {
+		w.OnChanged()
+	}
 }
 
-func (w *Board) Data() ([]byte, error) {
-	return json.Marshal(w)
+func (w *Board) changed() {
+	w.Refresh()
+	autoSave()
+
+	if w.OnChanged != nil {
+		w.OnChanged()
+	}
 }
 
+/* ================================================================================ Public methods */
 func (w *Board) Load(data []byte) error {
-	if err := json.Unmarshal(data, w); err != nil {
+	if err := w.Board.Load(data); err != nil {
 		return err
 	}
-	w.Refresh()
+	w.loaded()
 
 	return nil
 }
 
-func (w *Board) StageIndex(toFind *Stage) int {
-	for i, stage := range w.Stages {
-		if stage == toFind {
-			return i
-		}
+// Reload replaces the board by another version of it, keeping the activity log
+func (w *Board) Reload(data []byte) error {
+	if err := w.Board.Reload(data); err != nil {
+		return err
 	}
-	return -1
+	w.loaded()
+
+	return nil
 }
 
-func (w *Board) ItemStageIndex(toFind *Item) int {
-	for i, stage := range w.Stages {
-		if stage.ItemIndex(toFind) >= 0 {
-			return i
-		}
+// Execute runs the command on the board model, records it for undo and updates the widgets
+func (w *Board) Execute(command model.Command) bool {
+	if err := w.History.Execute(w.Board, command); err != nil {
+		dialog.ShowError(err, window)
+		return false
+	}
+	w.changed()
+
+	return true
+}
+
+func (w *Board) Undo() {
+	if !w.History.CanUndo() {
+		return
+	}
+
+	if err := w.History.Undo(w.Board); err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	w.changed()
+}
+
+func (w *Board) Redo() {
+	if !w.History.CanRedo() {
+		return
 	}
-	return -1
+
+	if err := w.History.Redo(w.Board); err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	w.changed()
+}
+
+func (w *Board) StageWidgets() []*Stage {
+	w.syncStages()
+	return w.stages
 }
 
 func (w *Board) ItemStage(toFind *Item) *Stage {
-	i := w.ItemStageIndex(toFind)
-	if i < 0 {
+	stage := w.Board.ItemStage(toFind.Item)
+	if stage == nil {
 		return nil
 	}
 
-	return w.Stages[i]
+	w.syncStages()
+	return w.stageWidget(stage)
+}
+
+// ItemWidget returns the widgets of the item and of the stage containing it
+func (w *Board) ItemWidget(item *model.Item) (*Item, *Stage) {
+	stage := w.Board.ItemStage(item)
+	if stage == nil {
+		return nil, nil
+	}
+
+	w.syncStages()
+	stageWidget := w.stageWidget(stage)
+	for _, itemWidget := range stageWidget.ItemWidgets() {
+		if itemWidget.Item == item {
+			return itemWidget, stageWidget
+		}
+	}
+	return nil, nil
+}
+
+// ShowItem scrolls the stage containing the item to make it visible
+func (w *Board) ShowItem(item *model.Item) {
+	if lane := w.ItemLane(item); lane != nil && lane.Collapsed {
+		lane.Collapsed = false
+		w.Refresh()
+		autoSave()
+	}
+
+	if itemWidget, stage := w.ItemWidget(item); itemWidget != nil {
+		stage.ScrollToItem(itemWidget)
+	}
 }
 
 func (w *Board) StageAtPosition(position fyne.Position) *Stage {
-	for _, stage := range w.Stages {
+	for _, stage := range w.StageWidgets() {
 		stageRect := Rectangle{stage.Position(), stage.Size()}
 
 		if stageRect.Contains(position) {
@@ -92,34 +193,43 @@ func (w *Board) StageAtPosition(position fyne.Position) *Stage {
 	return nil
 }
 
-func (w *Board) AppendStage(title string) {
-	stage := NewStage(title)
+func (w *Board) Rename(name string) {
+	w.Execute(&model.RenameBoardCommand{Name: name})
+}
 
-	w.Stages = append(w.Stages, stage)
-	w.Refresh()
-	autoSave()
+func (w *Board) AppendStage(title string) {
+	w.Execute(&model.AddStageCommand{Stage: model.NewStage(title), Index: -1})
 }
 
 func (w *Board) RemoveStage(toRemove *Stage) bool {
-	i := w.StageIndex(toRemove)
-	if i < 0 {
-		return false
-	}
+	return w.Execute(&model.RemoveStageCommand{Stage: toRemove.Stage})
+}
 
-	w.Stages = append(w.Stages[:i], w.Stages[i+1:]...)
-	w.Refresh()
-	autoSave()
+// MoveStage moves the stage before the given index, which is counted without the stage itself (-1 appends)
+func (w *Board) MoveStage(stage *Stage, index int) bool {
+	return w.Execute(&model.MoveStageCommand{Stage: stage.Stage, Index: index})
+}
 
-	return true
+func (w *Board) AppendLane(title string) {
+	w.Execute(&model.AddLaneCommand{Lane: model.NewLane(title), Index: -1})
 }
 
-func (w *Board) RemoveItem(toRemove *Item) {
-	for _, stage := range w.Stages {
-		if stage.RemoveItem(toRemove) {
-			w.Refresh()
-			return
-		}
-	}
+func (w *Board) RemoveLane(toRemove *model.Lane) bool {
+	return w.Execute(&model.RemoveLaneCommand{Lane: toRemove})
+}
+
+// IsItemShown reports whether the item is neither hidden by the filter nor in a collapsed lane
+func (w *Board) IsItemShown(item *model.Item) bool {
+	lane := w.ItemLane(item)
+	return w.Filter.Matches(item) && (lane == nil || !lane.Collapsed)
+}
+
+func (w *Board) RemoveItem(toRemove *Item) bool {
+	return w.Execute(&model.RemoveItemCommand{Item: toRemove.Item})
+}
+
+func (w *Board) MoveItem(item *Item, target *Stage, index int) bool {
+	return w.Execute(&model.MoveItemCommand{Item: item.Item, Target: target.Stage, Index: index})
 }
 
 func (w *Board) ShowCreateStageDialog() {
@@ -130,38 +240,58 @@ func (w *Board) ShowCreateStageDialog() {
 	)
 }
 
+func (w *Board) ShowCreateLaneDialog() {
+	ShowEntryDialog("New Swimlane", "Title ...", "",
+		func(text string) {
+			w.AppendLane(text)
+		},
+	)
+}
+
 func (w *Board) ApplyTagFilter() {
-	for _, stage := range w.Stages {
-		stage.SetFilterTags(w.FilterTags)
+	for _, stage := range w.StageWidgets() {
+		stage.SetFilter(w.Filter)
 	}
 }
 
-func (w *Board) SetTagFilter(tagEditString string) {
-	w.FilterTags = ParseTagEditString(tagEditString)
+// SetTagFilter parses and applies the filter query, on syntax errors the previous filter stays active
+func (w *Board) SetTagFilter(query string) error {
+	filter, err := model.ParseFilter(query)
+	if err != nil {
+		return err
+	}
+
+	w.Filter = filter
 	w.ApplyTagFilter()
+	return nil
 }
 
-func (w *Board) FilterTagIndex(toFind Tag) int {
-	for i, filterTag := range w.FilterTags {
-		if filterTag.Expression == toFind.Expression {
-			return i
-		}
+// SetFocus moves the keyboard focus to the item, or to the stage itself if the item is nil, and scrolls to it
+func (w *Board) SetFocus(stage *model.Stage, item *model.Item) {
+	w.FocusedStage = stage
+	w.FocusedItem = item
+	w.Refresh()
+
+	if item != nil {
+		w.ShowItem(item)
 	}
-	return -1
 }
 
-func (w *Board) ToggleFilterTag(tag Tag) {
-	i := w.FilterTagIndex(tag)
+// SetSearch highlights the matches of the search in all items, nil removes the highlights
+func (w *Board) SetSearch(search *model.Search, hit *model.Item) {
+	w.Search = search
+	w.SearchHit = hit
+	w.Refresh()
+}
 
-	if i < 0 {
-		w.FilterTags = append(w.FilterTags, tag)
-	} else {
-		w.FilterTags = append(w.FilterTags[:i], w.FilterTags[i+1:]...)
+func (w *Board) ToggleFilterTag(tag model.Tag) {
+	query := model.ToggleFilterTag(w.Filter.Query, tag)
+	if w.SetTagFilter(query) != nil {
+		return
 	}
-	w.ApplyTagFilter()
 
 	if w.OnFilterChanged != nil {
-		w.OnFilterChanged(ComposeTagEditString(w.FilterTags))
+		w.OnFilterChanged(query)
 	}
 }
 
@@ -181,21 +311,24 @@ func (w *Board) CreateRenderer() fyne.WidgetRenderer {
 
 	stageContainer := container.NewWithoutLayout()
 
-	stageCount := len(w.Stages)
-	if stageCount > 0 {
-		stageContainer.Layout = layout.NewGridLayout(stageCount)
+	stages := w.StageWidgets()
+	if len(stages) > 0 {
+		stageContainer.Layout = layout.NewGridLayout(len(stages))
 
-		for _, stage := range w.Stages {
+		for _, stage := range stages {
 			stageContainer.Add(stage)
 		}
 	}
 
-	return &boardRenderer{stageContainer, w}
+	return &boardRenderer{stageContainer, w.dragLayer.container, w}
 }
 
 func (r boardRenderer) Layout(size fyne.Size) {
 	r.stageContainer.Resize(size)
 	r.stageContainer.Move(fyne.NewPos(0, 0))
+
+	r.dragContainer.Resize(size)
+	r.dragContainer.Move(fyne.NewPos(0, 0))
 }
 
 func (r boardRenderer) MinSize() fyne.Size {
@@ -205,19 +338,26 @@ func (r boardRenderer) MinSize() fyne.Size {
 }
 
 func (r boardRenderer) Refresh() {
-	for _, stage := range r.stageContainer.Objects {
-		r.stageContainer.Remove(stage)
-	}
+	r.stageContainer.RemoveAll()
 
-	r.stageContainer.Layout = layout.NewGridLayout(len(r.w.Stages))
+	stages := r.w.StageWidgets()
+	r.stageContainer.Layout = layout.NewGridLayout(len(stages))
 
-	for _, stage := range r.w.Stages {
+	for _, stage := range stages {
 		r.stageContainer.Add(stage)
+		stage.Refresh()
+	}
+
+	/* The lane heights depend on the sections of all stages, so they are only known after refreshing every stage */
+	if len(r.w.Lanes) > 0 {
+		for _, stage := range stages {
+			stage.relayoutLanes()
+		}
 	}
 }
 
 func (r boardRenderer) Objects() []fyne.CanvasObject {
-	return []fyne.CanvasObject{r.stageContainer}
+	return []fyne.CanvasObject{r.stageContainer, r.dragContainer}
 }
 
 func (r boardRenderer) Destroy() {
@@ -0,0 +1,720 @@
+package main
+
+/* This file contains the command line interface to work with board files without opening a window */
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"bankan/model"
+)
+
+/* ================================================================================ Constants */
+const (
+	CLI_USAGE = `Usage: bankan [command] [arguments]
+
+Without a command the board window is opened.
+
+Commands:
+  list [-s STAGE] [-t FILTER] [-json] FILE
+      Lists the stages and items of the board, optionally only the items of one stage or matching the tag filter
+      (e.g. "Project=X AND NOT Status=blocked", "prio>=2 OR Urgent", "due<today").
+
+  add [-d DESCRIPTION] [-t TAGS] [-l LANE] [-start DATE] [-due DATE] [-r RECURRENCE] [-f] FILE STAGE TITLE
+      Appends a new item to the stage, which is created if it does not exist (as well as the board file and the
+      swimlane). Dates are given as YYYY-MM-DD. A recurring item ("daily", "weekly:mon,fri", "monthly:1,15",
+      "lunar:1,15", "tibetan:10,25") gets its next occurrence in the same stage once it is done, i.e. in the last
+      stage. The ID of the new item is printed.
+
+  move [-i INDEX] [-l LANE] [-f] FILE ITEM STAGE
+      Moves the item to the stage, to the end or to the given position (0 = top), and to the swimlane if given.
+
+  tag [-r] FILE ITEM TAGS
+      Adds the tags (e.g. "Urgent; Project=X") to the item, or removes them with -r.
+
+  recur FILE
+      Creates the next occurrences of the done recurring items, which are due until today (e.g. as daily cron job
+      for boards not open in a window). The IDs of the new items are printed.
+
+  export [-format FORMAT] [-o OUTPUT] FILE
+      Writes the board to standard output (or OUTPUT) as JSON in the current schema version, as Markdown document
+      ("markdown" or "md"), as CSV table with one row per item ("csv") or as standalone HTML page ("html"). The
+      format defaults to the extension of OUTPUT, otherwise JSON.
+
+  snapshot [-format FORMAT] [-w WIDTH] [-o OUTPUT] FILE
+      Renders the board like the window does, with all items of every stage, to standard output (or OUTPUT) as PNG
+      image ("png") or PDF document ("pdf") of the given width in pixels (default 1600). The format defaults to the
+      extension of OUTPUT, otherwise PNG. No display is needed, e.g. for nightly snapshots published by CI.
+
+  import [-format FORMAT] FILE SOURCE
+      Adds the stages and items of the board file SOURCE ("-" for standard input) to the board FILE, items of
+      stages with the same title are appended to the existing stage. SOURCE may also be a JSON export of Trello
+      ("trello"), my-personal-kanban ("mpk") or Kanbanapp ("kanbanapp"), the format is detected unless given, other
+      JSON is refused. What could not be imported is reported on standard error.
+
+  merge [-o OUTPUT] BASE MINE THEIRS
+      Three-way merge of two edited copies (MINE, THEIRS) of a common BASE board file. The result is written to
+      MINE (or OUTPUT), conflicting items keep the value of MINE and are tagged with "Conflict". The exit code is 1
+      if there are conflicts and 2 if a file is no board, so it can be used as git merge driver:
+        git config merge.bankan.driver "bankan merge %O %A %B"
+        echo "bankan_board.json merge=bankan" >> .gitattributes
+
+Adding or moving items to a stage which already holds as many items as its WIP limit allows fails, unless forced
+with -f. STAGE, ITEM and LANE are given by ID or by title. Changed boards are saved the same way as from the window, including
+backups (-backups COUNT, default 5).
+`
+)
+
+/* ================================================================================ Private functions */
+// runCommandLine executes the command given by the arguments and returns the exit code
+func runCommandLine(args []string) int {
+	switch args[0] {
+	case "list":
+		return runListCommand(args[1:])
+	case "add":
+		return runAddCommand(args[1:])
+	case "move":
+		return runMoveCommand(args[1:])
+	case "tag":
+		return runTagCommand(args[1:])
+	case "recur":
+		return runRecurCommand(args[1:])
+	case "export":
+		return runExportCommand(args[1:])
+	case "snapshot":
+		return runSnapshotCommand(args[1:])
+	case "import":
+		return runImportCommand(args[1:])
+	case "merge":
+		return runMergeCommand(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(CLI_USAGE)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", args[0], CLI_USAGE)
+		return 2
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, CLI_USAGE) }
+
+	return flags
+}
+
+// parseFlags parses the arguments, flags may be given before or after the positional arguments, which are returned
+// if their number is as expected
+func parseFlags(flags *flag.FlagSet, args []string, argCount int) ([]string, bool) {
+	positional := []string{}
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, false
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if len(positional) != argCount {
+		flags.Usage()
+		return nil, false
+	}
+	return positional, true
+}
+
+func commandError(format string, args ...any) int {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	return 1
+}
+
+// findStage returns the stage with the given ID or title
+func findStage(b *model.Board, reference string) *model.Stage {
+	if stage := b.StageByID(reference); stage != nil {
+		return stage
+	}
+	return b.StageByTitle(reference)
+}
+
+func findLane(b *model.Board, reference string) *model.Lane {
+	if lane := b.LaneByID(reference); lane != nil {
+		return lane
+	}
+	return b.LaneByTitle(reference)
+}
+
+// findItem returns the item with the given ID or the only item with the given title
+func findItem(b *model.Board, reference string) (*model.Item, error) {
+	if item := b.ItemByID(reference); item != nil {
+		return item, nil
+	}
+
+	var found *model.Item
+	for _, stage := range b.Stages {
+		for _, item := range stage.Items {
+			if item.Title != reference {
+				continue
+			}
+			if found != nil {
+				return nil, fmt.Errorf("there are several items titled %q, please use the ID", reference)
+			}
+			found = item
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %q", model.ErrItemNotFound, reference)
+	}
+	return found, nil
+}
+
+// loadBoardFile loads the board file like the window does, a missing file is an empty board if allowed
+func loadBoardFile(path string, allowMissing bool) (*model.Board, error) {
+	b, err := model.LoadFile(path)
+	if allowMissing && errors.Is(err, os.ErrNotExist) {
+		return model.NewBoard(""), nil
+	}
+	return b, err
+}
+
+func runListCommand(args []string) int {
+	flags := newFlagSet("list")
+	stageReference := flags.String("s", "", "only list the items of this stage")
+	filterQuery := flags.String("t", "", "only list the items matching the tag filter")
+	asJSON := flags.Bool("json", false, "print the listed stages and items as JSON")
+	args, ok := parseFlags(flags, args, 1)
+	if !ok {
+		return 2
+	}
+
+	b, err := loadBoardFile(args[0], false)
+	if err != nil {
+		return commandError("Could not load board %s: %v", args[0], err)
+	}
+
+	stages := b.Stages
+	if *stageReference != "" {
+		stage := findStage(b, *stageReference)
+		if stage == nil {
+			return commandError("%v: %q", model.ErrStageNotFound, *stageReference)
+		}
+		stages = []*model.Stage{stage}
+	}
+
+	filter, err := model.ParseFilter(*filterQuery)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid filter: %v\n", err)
+		return 2
+	}
+
+	listed := make([]*model.Stage, len(stages))
+	for i, stage := range stages {
+		listed[i] = &model.Stage{ID: stage.ID, Created: stage.Created, Modified: stage.Modified, Title: stage.Title, WIPLimit: stage.WIPLimit, Items: stage.FilterItems(filter)}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(listed); err != nil {
+			return commandError("Could not encode the board: %v", err)
+		}
+		return 0
+	}
+
+	for i, stage := range listed {
+		fmt.Printf("%s  [%s]", stage.ID, stage.Title)
+		if stage.WIPLimit > 0 {
+			fmt.Printf("  %d/%d", len(stages[i].Items), stage.WIPLimit)
+		}
+		fmt.Println()
+		for _, item := range stage.Items {
+			fmt.Printf("%s    %s", item.ID, item.Title)
+			if lane := b.ItemLane(item); lane != nil {
+				fmt.Printf("  {%s}", lane.Title)
+			}
+			if !item.Start.IsZero() {
+				fmt.Printf("  start %s", model.FormatDate(item.Start))
+			}
+			if !item.Due.IsZero() {
+				fmt.Printf("  due %s", model.FormatDate(item.Due))
+				if item.DueState(time.Now()) == model.DueOverdue {
+					fmt.Print(" (overdue)")
+				}
+			}
+			if item.Recurrence != nil {
+				fmt.Printf("  ↻ %s", item.Recurrence)
+			}
+			if done, total := item.ChecklistProgress(); total > 0 {
+				fmt.Printf("  [%d/%d]", done, total)
+			}
+			if len(item.Tags) > 0 {
+				fmt.Printf("  (%s)", strings.TrimSuffix(model.ComposeTagEditString(item.Tags), "; "))
+			}
+			fmt.Println()
+		}
+	}
+
+	return 0
+}
+
+func runAddCommand(args []string) int {
+	flags := newFlagSet("add")
+	description := flags.String("d", "", "description of the new item")
+	tagEditString := flags.String("t", "", "tags of the new item, separated by semicolons")
+	laneReference := flags.String("l", "", "swimlane of the new item, which is created if it does not exist")
+	startText := flags.String("start", "", "start date of the new item (YYYY-MM-DD)")
+	dueText := flags.String("due", "", "due date of the new item (YYYY-MM-DD)")
+	recurrenceText := flags.String("r", "", "recurrence of the new item")
+	force := flags.Bool("f", false, "add the item even if it exceeds the WIP limit of the stage")
+	backups := flags.Int("backups", DEFAULT_BACKUP_COUNT, "number of backups to keep")
+	args, ok := parseFlags(flags, args, 3)
+	if !ok {
+		return 2
+	}
+
+	start, err := model.ParseDate(*startText)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid start date: %q\n", *startText)
+		return 2
+	}
+	due, err := model.ParseDate(*dueText)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid due date: %q\n", *dueText)
+		return 2
+	}
+	recurrence, err := model.ParseRecurrence(*recurrenceText)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	path := args[0]
+	b, err := loadBoardFile(path, true)
+	if err != nil {
+		return commandError("Could not load board %s: %v", path, err)
+	}
+
+	stage := findStage(b, args[1])
+	if stage == nil {
+		stage = model.NewStage(args[1])
+		b.AppendStage(stage)
+	}
+	if !*force && stage.ExceedsWIPLimit(1) {
+		return commandError("Could not add item: %v: %q holds %d of %d items", model.ErrWIPLimitExceeded, stage.Title, len(stage.Items), stage.WIPLimit)
+	}
+
+	item := model.NewItem(args[2], model.ParseTagEditString(*tagEditString), *description, model.DefaultItemStyle, "Normal")
+	if *laneReference != "" {
+		lane := findLane(b, *laneReference)
+		if lane == nil {
+			lane = model.NewLane(*laneReference)
+			b.InsertLane(-1, lane)
+		}
+		item.LaneID = lane.ID
+	}
+	item.SetDates(start, due)
+	if recurrence != nil {
+		recurrence.StageID = stage.ID
+		item.SetRecurrence(recurrence)
+	}
+	(&model.AddItemCommand{Stage: stage, Item: item, Index: -1}).Do(b)
+
+	if err := model.SaveFile(b, path, *backups); err != nil {
+		return commandError("Could not save board %s: %v", path, err)
+	}
+
+	fmt.Println(item.ID)
+	return 0
+}
+
+func runMoveCommand(args []string) int {
+	flags := newFlagSet("move")
+	index := flags.Int("i", -1, "position inside the target stage, the end if negative")
+	laneReference := flags.String("l", "", "target swimlane, the item keeps its swimlane if not given")
+	force := flags.Bool("f", false, "move the item even if it exceeds the WIP limit of the stage")
+	backups := flags.Int("backups", DEFAULT_BACKUP_COUNT, "number of backups to keep")
+	args, ok := parseFlags(flags, args, 3)
+	if !ok {
+		return 2
+	}
+
+	path := args[0]
+	b, err := loadBoardFile(path, false)
+	if err != nil {
+		return commandError("Could not load board %s: %v", path, err)
+	}
+
+	item, err := findItem(b, args[1])
+	if err != nil {
+		return commandError("Could not move item: %v", err)
+	}
+
+	target := findStage(b, args[2])
+	if target == nil {
+		return commandError("Could not move item: %v: %q", model.ErrStageNotFound, args[2])
+	}
+	if !*force && target != b.ItemStage(item) && target.ExceedsWIPLimit(1) {
+		return commandError("Could not move item: %v: %q holds %d of %d items", model.ErrWIPLimitExceeded, target.Title, len(target.Items), target.WIPLimit)
+	}
+
+	var lane *model.Lane
+	if *laneReference != "" {
+		if lane = findLane(b, *laneReference); lane == nil {
+			return commandError("Could not move item: %v: %q", model.ErrLaneNotFound, *laneReference)
+		}
+	}
+
+	targetIndex := *index
+	if targetIndex < 0 {
+		targetIndex = len(target.Items)
+	}
+
+	if err := (&model.MoveItemCommand{Item: item, Target: target, Index: targetIndex, Lane: lane}).Do(b); err != nil {
+		return commandError("Could not move item: %v", err)
+	}
+
+	if err := model.SaveFile(b, path, *backups); err != nil {
+		return commandError("Could not save board %s: %v", path, err)
+	}
+
+	return 0
+}
+
+func runTagCommand(args []string) int {
+	flags := newFlagSet("tag")
+	remove := flags.Bool("r", false, "remove the tags instead of adding them")
+	backups := flags.Int("backups", DEFAULT_BACKUP_COUNT, "number of backups to keep")
+	args, ok := parseFlags(flags, args, 3)
+	if !ok {
+		return 2
+	}
+
+	path := args[0]
+	b, err := loadBoardFile(path, false)
+	if err != nil {
+		return commandError("Could not load board %s: %v", path, err)
+	}
+
+	item, err := findItem(b, args[1])
+	if err != nil {
+		return commandError("Could not tag item: %v", err)
+	}
+
+	changedTags := model.ParseTagEditString(args[2])
+	tags := []model.Tag{}
+	for _, tag := range item.Tags {
+		if !*remove || !slices.Contains(changedTags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if !*remove {
+		for _, tag := range changedTags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	(&model.EditItemCommand{Item: item, Title: item.Title, Tags: tags, Description: item.Description, Style: item.Style, DataType: item.DataType, Start: item.Start, Due: item.Due, Checklist: item.Checklist}).Do(b)
+
+	if err := model.SaveFile(b, path, *backups); err != nil {
+		return commandError("Could not save board %s: %v", path, err)
+	}
+
+	return 0
+}
+
+func runRecurCommand(args []string) int {
+	flags := newFlagSet("recur")
+	backups := flags.Int("backups", DEFAULT_BACKUP_COUNT, "number of backups to keep")
+	args, ok := parseFlags(flags, args, 1)
+	if !ok {
+		return 2
+	}
+
+	path := args[0]
+	b, err := loadBoardFile(path, false)
+	if err != nil {
+		return commandError("Could not load board %s: %v", path, err)
+	}
+
+	today := time.Now()
+	items := b.DueRecurrences(today, getRecurrenceCalendars())
+	if len(items) < 1 {
+		return 0
+	}
+
+	command := &model.CreateOccurrencesCommand{Items: items, Today: today, Calendars: getRecurrenceCalendars()}
+	if err := command.Do(b); err != nil {
+		return commandError("Could not create occurrences: %v", err)
+	}
+
+	if err := model.SaveFile(b, path, *backups); err != nil {
+		return commandError("Could not save board %s: %v", path, err)
+	}
+
+	for _, occurrence := range command.Occurrences() {
+		fmt.Println(occurrence.ID)
+	}
+	return 0
+}
+
+func runExportCommand(args []string) int {
+	flags := newFlagSet("export")
+	output := flags.String("o", "", "file to write to instead of standard output")
+	formatName := flags.String("format", "", "json, markdown, csv or html, by default from the extension of the output file")
+	args, ok := parseFlags(flags, args, 1)
+	if !ok {
+		return 2
+	}
+
+	format := model.ExportFormatOfPath(*output)
+	if *formatName != "" {
+		var err error
+		if format, err = model.ParseExportFormat(*formatName); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 2
+		}
+	}
+
+	b, err := loadBoardFile(args[0], false)
+	if err != nil {
+		return commandError("Could not load board %s: %v", args[0], err)
+	}
+
+	data, err := b.Export(format)
+	if err != nil {
+		return commandError("Could not export board: %v", err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = model.WriteFileAtomic(*output, data)
+	}
+	if err != nil {
+		return commandError("Could not export board: %v", err)
+	}
+
+	return 0
+}
+
+func runSnapshotCommand(args []string) int {
+	flags := newFlagSet("snapshot")
+	output := flags.String("o", "", "file to write to instead of standard output")
+	formatName := flags.String("format", "", "png or pdf, by default from the extension of the output file")
+	width := flags.Int("w", SNAPSHOT_DEFAULT_WIDTH, "width of the snapshot in pixels")
+	args, ok := parseFlags(flags, args, 1)
+	if !ok {
+		return 2
+	}
+
+	format := SnapshotFormatOfPath(*output)
+	if *formatName != "" {
+		var err error
+		if format, err = ParseSnapshotFormat(*formatName); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 2
+		}
+	}
+	if *width < SNAPSHOT_MIN_WIDTH {
+		fmt.Fprintf(os.Stderr, "The width must be at least %d pixels\n", SNAPSHOT_MIN_WIDTH)
+		return 2
+	}
+
+	b, err := loadBoardFile(args[0], false)
+	if err != nil {
+		return commandError("Could not load board %s: %v", args[0], err)
+	}
+
+	data := &bytes.Buffer{}
+	if err := WriteBoardSnapshot(data, b, float32(*width), format); err != nil {
+		return commandError("Could not render board: %v", err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data.Bytes())
+	} else {
+		err = model.WriteFileAtomic(*output, data.Bytes())
+	}
+	if err != nil {
+		return commandError("Could not write snapshot: %v", err)
+	}
+
+	return 0
+}
+
+func runImportCommand(args []string) int {
+	flags := newFlagSet("import")
+	backups := flags.Int("backups", DEFAULT_BACKUP_COUNT, "number of backups to keep")
+	formatName := flags.String("format", "", "bankan, trello, mpk or kanbanapp, detected by default")
+	args, ok := parseFlags(flags, args, 2)
+	if !ok {
+		return 2
+	}
+
+	var format model.ImportFormat
+	if *formatName != "" {
+		var err error
+		if format, err = model.ParseImportFormat(*formatName); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 2
+		}
+	}
+
+	path := args[0]
+	b, err := loadBoardFile(path, true)
+	if err != nil {
+		return commandError("Could not load board %s: %v", path, err)
+	}
+
+	var data []byte
+	if args[1] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[1])
+	}
+	var imported *model.Board
+	var report *model.ImportReport
+	if err == nil {
+		imported, report, err = model.Import(data, format)
+	}
+	if err != nil {
+		return commandError("Could not load board %s: %v", args[1], err)
+	}
+
+	importBoard(b, imported)
+	if report.Format != model.ImportBanKan || len(report.Unmapped) > 0 {
+		fmt.Fprintln(os.Stderr, report)
+	}
+
+	if err := model.SaveFile(b, path, *backups); err != nil {
+		return commandError("Could not save board %s: %v", path, err)
+	}
+
+	return 0
+}
+
+// importBoard adds the stages and items of the imported board, identifiers already in use get replaced by new ones
+func importBoard(b *model.Board, imported *model.Board) {
+	if b.Name == "" {
+		b.Name = imported.Name
+	}
+
+	/* Lanes with the same title are joined, so the lane IDs of the imported items are mapped to the existing lanes */
+	laneIDs := map[string]string{}
+	for _, importedLane := range imported.Lanes {
+		importedID := importedLane.ID
+		lane := b.LaneByTitle(importedLane.Title)
+		if lane == nil {
+			lane = importedLane
+			if b.LaneByID(lane.ID) != nil {
+				lane.ID = model.NewID()
+			}
+			b.InsertLane(-1, lane)
+		}
+		laneIDs[importedID] = lane.ID
+	}
+
+	for _, importedStage := range imported.Stages {
+		stage := b.StageByTitle(importedStage.Title)
+		if stage == nil {
+			stage = importedStage
+			if b.StageByID(stage.ID) != nil {
+				stage.ID = model.NewID()
+			}
+			items := stage.Items
+			stage.Items = nil
+			b.AppendStage(stage)
+			importedStage = &model.Stage{Items: items}
+		}
+
+		for _, item := range importedStage.Items {
+			if b.ItemByID(item.ID) != nil {
+				item.ID = model.NewID()
+			}
+			item.LaneID = laneIDs[item.LaneID]
+			stage.AppendItem(item)
+		}
+	}
+}
+
+// loadMergeBoardFile loads a board to merge, other JSON files are refused instead of being merged as empty board - only
+// the common ancestor may be an empty file, which git passes if there is none
+func loadMergeBoardFile(path string, allowEmpty bool) (*model.Board, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || (allowEmpty && len(data) == 0) {
+		return nil, err
+	}
+
+	if err := model.CheckBoardData(data); err != nil {
+		return nil, err
+	}
+
+	b := &model.Board{}
+	if err := b.Load(data); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func runMergeCommand(args []string) int {
+	flags := newFlagSet("merge")
+	output := flags.String("o", "", "file to write the merged board to instead of MINE")
+	args, ok := parseFlags(flags, args, 3)
+	if !ok {
+		return 2
+	}
+
+	base, err := loadMergeBoardFile(args[0], true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load base board %s: %v\n", args[0], err)
+		return 2
+	}
+
+	mine, err := loadMergeBoardFile(args[1], false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load board %s: %v\n", args[1], err)
+		return 2
+	}
+
+	theirs, err := loadMergeBoardFile(args[2], false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load board %s: %v\n", args[2], err)
+		return 2
+	}
+
+	merged, conflicts := model.Merge(base, mine, theirs)
+	model.MarkConflicts(merged, conflicts)
+
+	outputPath := *output
+	if outputPath == "" {
+		outputPath = args[1]
+	}
+
+	if err := model.SaveFile(merged, outputPath, 0); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save merged board %s: %v\n", outputPath, err)
+		return 2
+	}
+
+	for _, conflict := range conflicts {
+		fmt.Fprintln(os.Stderr, "CONFLICT", conflict)
+	}
+	if len(conflicts) > 0 {
+		return 1
+	}
+
+	return 0
+}
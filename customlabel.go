@@ -35,14 +35,23 @@ type CustomLabel struct {
 	TextSize                         float32
 	TextStyle                        fyne.TextStyle
 	BackgroundPaddings, TextPaddings Paddings
+	Highlighter                      func(line string) [][]int
 }
 
 
 /* ================================================================================ Private types */
 type customLabelRenderer struct {
-	background   *canvas.Rectangle
-	textCanvases *[]*canvas.Text
-	w            *CustomLabel
+	background      *canvas.Rectangle
+	textCanvases    *[]*canvas.Text
+	highlights      *[]*canvas.Rectangle
+	highlightRanges *[]highlightRange
+	w               *CustomLabel
+}
+
+
+/* highlightRange is the byte range of a highlighted match inside a wrapped line */
+type highlightRange struct {
+	line, start, end int
 }
 
 
@@ -200,7 +209,10 @@ func (w *CustomLabel) CreateRenderer() fyne.WidgetRenderer {
 		textCanvases[i] = textCanvas
 	}
 
-	return &customLabelRenderer{ background, &textCanvases, w }
+	highlights      := []*canvas.Rectangle{}
+	highlightRanges := []highlightRange{}
+
+	return &customLabelRenderer{ background, &textCanvases, &highlights, &highlightRanges, w }
 }
 
 
@@ -221,6 +233,8 @@ func (r customLabelRenderer) Layout(size fyne.Size) {
 		textCanvas.Move(fyne.NewPos(r.w.TextPaddings.Left, heightOffset))
 		heightOffset += lineHeight
 	}
+
+	r.layoutHighlights(size)
 }
 
 
@@ -243,6 +257,7 @@ func (r customLabelRenderer) MinSize() fyne.Size {
 func (r customLabelRenderer) Refresh() {
 	r.background.FillColor   = r.w.Style.Background
 	r.background.StrokeColor = r.w.Style.Stroke
+	r.background.StrokeWidth = r.w.Style.StrokeWidth
 	r.background.Refresh()
 
 	lines         := strings.Split(r.w.Text, "\n")
@@ -273,16 +288,67 @@ func (r customLabelRenderer) Refresh() {
 	}
 
 	*r.textCanvases = (*r.textCanvases)[:len(linesWrapped)]
+
+	r.refreshHighlights(linesWrapped)
+}
+
+
+func (r customLabelRenderer) refreshHighlights(linesWrapped []string) {
+	*r.highlightRanges = (*r.highlightRanges)[:0]
+
+	if r.w.Highlighter != nil {
+		for i, line := range linesWrapped {
+			for _, match := range r.w.Highlighter(line) {
+				*r.highlightRanges = append(*r.highlightRanges, highlightRange{ i, match[0], match[1] })
+			}
+		}
+	}
+
+	for len(*r.highlights) < len(*r.highlightRanges) {
+		*r.highlights = append(*r.highlights, canvas.NewRectangle(color.RGBA{255, 200, 0, 160}))
+	}
+	*r.highlights = (*r.highlights)[:len(*r.highlightRanges)]
+	r.layoutHighlights(r.w.Size())
+}
+
+
+/* layoutHighlights places the highlights behind the matched parts of the lines, taking the alignment into account */
+func (r customLabelRenderer) layoutHighlights(size fyne.Size) {
+	textSize   := fyne.NewSize(size.Width - r.w.TextPaddings.Left - r.w.TextPaddings.Right, size.Height - r.w.TextPaddings.Top - r.w.TextPaddings.Bottom)
+	lineHeight := textSize.Height / float32(len(*r.textCanvases))
+
+	for i, highlight := range *r.highlights {
+		hit       := (*r.highlightRanges)[i]
+		line      := (*r.textCanvases)[hit.line].Text
+		lineWidth := fyne.MeasureText(line, r.w.TextSize, r.w.TextStyle).Width
+		offset    := r.w.TextPaddings.Left
+
+		switch r.w.Alignment {
+			case fyne.TextAlignCenter:
+				offset += (textSize.Width - lineWidth) / 2
+			case fyne.TextAlignTrailing:
+				offset += textSize.Width - lineWidth
+		}
+
+		startX := fyne.MeasureText(line[:hit.start], r.w.TextSize, r.w.TextStyle).Width
+		width  := fyne.MeasureText(line[hit.start:hit.end], r.w.TextSize, r.w.TextStyle).Width
+
+		highlight.Resize(fyne.NewSize(width, lineHeight))
+		highlight.Move(fyne.NewPos(offset + startX, r.w.TextPaddings.Top + float32(hit.line) * lineHeight))
+	}
 }
 
 
 func (r customLabelRenderer) Objects() []fyne.CanvasObject {
-	objectCount := len(*r.textCanvases) + 1
-	objects     := make([]fyne.CanvasObject, objectCount)
-	objects[0]   = r.background
+	objects := make([]fyne.CanvasObject, 0, len(*r.highlights) + len(*r.textCanvases) + 1)
+	objects  = append(objects, r.background)
 
-	for i, textCanvas := range *r.textCanvases {
-		objects[i + 1] = textCanvas
+	for _, highlight := range *r.highlights {
+		objects = append(objects, highlight)
+	}
+
+	for _, textCanvas := range *r.textCanvases {
+		objects = append(objects, textCanvas)
 	}
 
 	return objects
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"bankan/model"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
@@ -193,6 +195,34 @@ func getLunarInfo(date time.Time) string {
 	return fmt.Sprintf("%d/%d/%d%s", lunarDate.Year, lunarDate.Month, lunarDate.Day, solarTermInfo)
 }
 
+// 农历日期缓存，每次生成整月的日历，避免为每一天重复计算
+var lunarDayCache = map[string]int{}
+
+// 获取农历日（初一为1），用于按农历日期重复的item
+func getLunarDay(date time.Time) int {
+	key := date.Format("2006-01-02")
+	if day, ok := lunarDayCache[key]; ok {
+		return day
+	}
+
+	cal := gocalendar.DefaultCalendar()
+	for _, item := range cal.GenerateWithDate(date.Year(), int(date.Month()), date.Day()) {
+		lunarDayCache[item.Time.Format("2006-01-02")] = item.LunarDate.Day
+	}
+	return lunarDayCache[key]
+}
+
+// 获取藏历日，用于按藏历日期重复的item
+func getTibetanDay(date time.Time) int {
+	_, _, tibetanDay := solarToTibetan(date.Year(), int(date.Month()), date.Day())
+	return tibetanDay
+}
+
+// 重复item使用的农历和藏历换算
+func getRecurrenceCalendars() model.Calendars {
+	return model.Calendars{LunarDay: getLunarDay, TibetanDay: getTibetanDay}
+}
+
 // 获取农历十斋日信息
 func getLunarFastingDayInfo(lunarDay int, lang string) string {
 	// 十斋日：初一、初八、十四、十五、十八、二十三、二十四、二十八、二十九、三十
@@ -546,6 +576,25 @@ func ShowConfirmDialog(title, text string, confirmedCallback func()) {
 	)
 }
 
+func ShowChoiceDialog(title, text string, choices []string, chosenCallback func(choice int)) {
+	buttons := make([]fyne.CanvasObject, len(choices))
+	choiceDialog := dialog.NewCustomWithoutButtons(title, widget.NewLabel(text), window)
+
+	for i, choice := range choices {
+		buttons[i] = widget.NewButton(choice,
+			func() {
+				choiceDialog.Hide()
+				if chosenCallback != nil {
+					chosenCallback(i)
+				}
+			},
+		)
+	}
+
+	choiceDialog.SetButtons(buttons)
+	choiceDialog.Show()
+}
+
 func ShowEntryDialog(title, placeholder, text string, confirmedCallback func(text string)) {
 	entry := widget.NewEntry()
 	entry.SetPlaceHolder(placeholder)
@@ -564,6 +613,64 @@ func ShowEntryDialog(title, placeholder, text string, confirmedCallback func(tex
 	window.Canvas().Focus(entry)
 }
 
+func ShowSelectDialog(title, placeholder string, options []string, confirmedCallback func(index int)) {
+	selectEntry := widget.NewSelect(options, nil)
+	selectEntry.PlaceHolder = placeholder
+
+	dialogContainer := container.NewVBox(selectEntry, canvas.NewText("", color.Black))
+
+	dialog.ShowCustomConfirm(title, "OK", "Cancel", dialogContainer,
+		func(confirmed bool) {
+			if confirmed && confirmedCallback != nil && selectEntry.SelectedIndex() >= 0 {
+				confirmedCallback(selectEntry.SelectedIndex())
+			}
+		}, window,
+	)
+}
+
+func ShowEntrySelectDialog(title, placeholder, text, selectPlaceholder string, options []string, selected int, confirmedCallback func(text string, index int)) {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(placeholder)
+	entry.SetText(text)
+
+	selectEntry := widget.NewSelect(options, nil)
+	selectEntry.PlaceHolder = selectPlaceholder
+	if selected >= 0 && selected < len(options) {
+		selectEntry.SetSelectedIndex(selected)
+	}
+
+	dialogContainer := container.NewVBox(entry, selectEntry, canvas.NewText("", color.Black))
+
+	dialog.ShowCustomConfirm(title, "OK", "Cancel", dialogContainer,
+		func(confirmed bool) {
+			if confirmed && confirmedCallback != nil {
+				confirmedCallback(entry.Text, selectEntry.SelectedIndex())
+			}
+		}, window,
+	)
+
+	window.Canvas().Focus(entry)
+}
+
+// ShowActivityDialog lists the activities, newest first
+func ShowActivityDialog(title string, activities []model.Activity) {
+	text := "No activity recorded yet."
+	if len(activities) > 0 {
+		entries := make([]string, len(activities))
+		for i, activity := range activities {
+			entries[len(activities)-1-i] = activity.String()
+		}
+		text = strings.Join(entries, "\n\n")
+	}
+
+	label := widget.NewLabel(text)
+	label.Wrapping = fyne.TextWrapWord
+
+	activityDialog := dialog.NewCustom(title, "Close", container.NewVScroll(label), window)
+	activityDialog.Resize(fyne.NewSize(600, 400))
+	activityDialog.Show()
+}
+
 func ShowColorPickerDialog(title, message string, preselected color.RGBA, confirmedCallback func(selected color.RGBA)) {
 	colorPickerDialog := dialog.NewColorPicker(title, message,
 		func(c color.Color) {
@@ -577,13 +684,13 @@ func ShowColorPickerDialog(title, message string, preselected color.RGBA, confir
 	colorPickerDialog.SetColor(preselected)
 }
 
-func ShowItemDialog(dialogPrefix, title, tagEditString, description string, style ItemStyle, confirmedCallback func(title, tagEditString, description string, style ItemStyle)) {
-	ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description, style, "Normal", func(title, tagEditString, description string, style ItemStyle, dataType string) {
+func ShowItemDialog(dialogPrefix, title, tagEditString, description string, style model.ItemStyle, confirmedCallback func(title, tagEditString, description string, style model.ItemStyle)) {
+	ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description, "", style, "Normal", time.Time{}, time.Time{}, func(title, tagEditString, description, checklistEditString string, style model.ItemStyle, dataType string, start, due time.Time) {
 		confirmedCallback(title, tagEditString, description, style)
 	})
 }
 
-func ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description string, style ItemStyle, currentDataType string, confirmedCallback func(title, tagEditString, description string, style ItemStyle, dataType string)) {
+func ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description, checklistEditString string, style model.ItemStyle, currentDataType string, start, due time.Time, confirmedCallback func(title, tagEditString, description, checklistEditString string, style model.ItemStyle, dataType string, start, due time.Time)) {
 	titleEntry := widget.NewEntry()
 	titleEntry.SetPlaceHolder("Title ...")
 	titleEntry.SetText(title)
@@ -613,6 +720,30 @@ func ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description
 	// 设置描述输入框的最小尺寸为两倍高度
 	descriptionEntry.Resize(fyne.NewSize(descriptionEntry.MinSize().Width, 400))
 
+	// Markdown预览，与描述输入框切换显示
+	descriptionPreview, descriptionPreviewText := newMarkdownView("", theme.Color(theme.ColorNameForeground))
+	descriptionPreviewScroll := container.NewVScroll(descriptionPreview)
+	descriptionPreviewScroll.Hide()
+	previewCheck := widget.NewCheck("Preview", func(checked bool) {
+		if checked {
+			descriptionPreviewText.ParseMarkdown(descriptionEntry.Text)
+			descriptionEntry.Hide()
+			descriptionPreviewScroll.Show()
+		} else {
+			descriptionPreviewScroll.Hide()
+			descriptionEntry.Show()
+		}
+	})
+	if board.PlainText {
+		previewCheck.Hide()
+	}
+	descriptionContainer := container.NewBorder(nil, previewCheck, nil, nil, container.NewStack(descriptionEntry, descriptionPreviewScroll))
+
+	checklistEntry := widget.NewMultiLineEntry()
+	checklistEntry.SetPlaceHolder("[ ] Open subtask\n[x] Done subtask\n...")
+	checklistEntry.SetText(checklistEditString)
+	checklistEntry.SetMinRowsVisible(3)
+
 	foregroundColor := style.Foreground
 	foregroundColorButton := widget.NewButtonWithIcon("Foregound", theme.ColorPaletteIcon(),
 		func() {
@@ -637,8 +768,12 @@ func ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description
 
 	buttonContainer := container.NewGridWithColumns(2, foregroundColorButton, backgroundColorButton)
 
+	startEntry := newItemDateEntry("Start date ...", start)
+	dueEntry := newItemDateEntry("Due date ...", due)
+	dateContainer := container.NewGridWithColumns(2, startEntry, dueEntry)
+
 	// 创建一个固定尺寸的容器来包装所有组件，实现对话框尺寸加倍
-	contentContainer := container.NewVBox(titleEntry, dataTypeSelect, tagsEntry, descriptionEntry, buttonContainer)
+	contentContainer := container.NewVBox(titleEntry, dataTypeSelect, tagsEntry, descriptionContainer, checklistEntry, dateContainer, buttonContainer)
 	// 使用Border容器设置固定尺寸，宽度和高度都比原来大
 	dialogContainer := container.NewBorder(nil, nil, nil, nil, contentContainer)
 	dialogContainer.Resize(fyne.NewSize(600, 400)) // 设置对话框容器的固定尺寸
@@ -685,7 +820,7 @@ func ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description
 					}
 				}
 
-				confirmedCallback(finalTitle, finalTagString, descriptionEntry.Text, ItemStyle{foregroundColor, backgroundColor}, selectedType)
+				confirmedCallback(finalTitle, finalTagString, descriptionEntry.Text, checklistEntry.Text, model.ItemStyle{Foreground: foregroundColor, Background: backgroundColor}, selectedType, itemDate(startEntry), itemDate(dueEntry))
 			}
 		}, window,
 	)
@@ -696,7 +831,11 @@ func ShowItemDialogWithDataType(dialogPrefix, title, tagEditString, description
 func ShowFileOpenConfirmDialog(title, text string, defaultFileURI fyne.URI, confirmedCallback func(reader fyne.URIReadCloser)) {
 	fileDialog := dialog.NewFileOpen(
 		func(reader fyne.URIReadCloser, err error) {
-			if reader != nil && err == nil {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if reader != nil {
 				ShowConfirmDialog(title, text,
 					func() {
 						if confirmedCallback != nil {
@@ -719,7 +858,11 @@ func ShowFileOpenConfirmDialog(title, text string, defaultFileURI fyne.URI, conf
 func ShowSaveAsDialog(defaultFileURI fyne.URI, confirmedCallback func(writer fyne.URIWriteCloser)) {
 	fileDialog := dialog.NewFileSave(
 		func(writer fyne.URIWriteCloser, err error) {
-			if writer != nil && err == nil && confirmedCallback != nil {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if writer != nil && confirmedCallback != nil {
 				confirmedCallback(writer)
 			}
 		}, window,
@@ -736,6 +879,31 @@ func ShowSaveAsDialog(defaultFileURI fyne.URI, confirmedCallback func(writer fyn
 	fileDialog.Show()
 }
 
+// ShowExportDialog asks for the file to export the board to, named like the default file with the given extension
+func ShowExportDialog(defaultFileURI fyne.URI, extension string, confirmedCallback func(writer fyne.URIWriteCloser)) {
+	fileDialog := dialog.NewFileSave(
+		func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if writer != nil && confirmedCallback != nil {
+				confirmedCallback(writer)
+			}
+		}, window,
+	)
+
+	if defaultFileURI != nil {
+		fileDialog.SetFileName(strings.TrimSuffix(defaultFileURI.Name(), defaultFileURI.Extension()) + extension)
+		fileDialog.SetLocation(getParentListableURI(defaultFileURI))
+	} else {
+		fileDialog.SetFileName("bankan_board" + extension)
+	}
+
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{extension}))
+	fileDialog.Show()
+}
+
 /* ================================================================================ Calendar Conversion Functions */
 
 // 注意：农历和节气计算现在使用gocalendar库提供精确算法
@@ -932,3 +1100,23 @@ func getParentListableURI(file fyne.URI) fyne.ListableURI {
 
 	return dirListableURI
 }
+
+// newItemDateEntry creates a date entry with calendar drop-down, preset to the date unless it is zero
+func newItemDateEntry(placeholder string, date time.Time) *widget.DateEntry {
+	entry := widget.NewDateEntry()
+	entry.SetPlaceHolder(placeholder)
+	if !date.IsZero() {
+		entry.SetDate(&date)
+	}
+
+	return entry
+}
+
+// itemDate returns the date of the entry as local calendar day, or the zero time if no valid date is entered
+func itemDate(entry *widget.DateEntry) time.Time {
+	if entry.Date == nil || entry.Text == "" {
+		return time.Time{}
+	}
+
+	return time.Date(entry.Date.Year(), entry.Date.Month(), entry.Date.Day(), 0, 0, 0, 0, time.Local)
+}
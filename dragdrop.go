@@ -0,0 +1,371 @@
+package main
+
+/* This file contains the drag and drop of items within and between stages and swimlanes, with a live preview of the dragged item,
+   a highlight of the target stage, an insertion marker between the items and auto-scrolling near the stage edges,
+   as well as the reordering of stages dragged by their title bar with an insertion marker between the stages */
+
+/* ================================================================================ Imports */
+import (
+	"image/color"
+	"time"
+
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+)
+
+/* ================================================================================ Constants */
+const (
+	DRAG_AUTO_SCROLL_EDGE     = 40
+	DRAG_AUTO_SCROLL_STEP     = 12
+	DRAG_AUTO_SCROLL_INTERVAL = 30 * time.Millisecond
+)
+
+/* ================================================================================ Private types */
+// dragLayer holds the feedback objects drawn by the board on top of the stages while an item is dragged
+type dragLayer struct {
+	container      *fyne.Container
+	stageHighlight *canvas.Rectangle
+	marker         *canvas.Rectangle
+	preview        *canvas.Rectangle
+	previewText    *canvas.Text
+}
+
+// dropTarget is the place an item would be dropped at, the index is counted without the dragged item (-1 appends),
+// the lane is nil if the board has no lanes
+type dropTarget struct {
+	stage   *Stage
+	lane    *model.Lane
+	index   int
+	markerY float32
+}
+
+type dragState struct {
+	item       *Item
+	grabOffset fyne.Position
+	pointer    fyne.Position
+	target     *dropTarget
+	done       chan struct{}
+}
+
+// stageDropTarget is the place a stage would be dropped at, the index is counted without the dragged stage (-1 appends)
+type stageDropTarget struct {
+	index   int
+	markerX float32
+}
+
+type stageDragState struct {
+	stage      *Stage
+	grabOffset fyne.Position
+	pointer    fyne.Position
+}
+
+/* ================================================================================ Private variables */
+var activeDrag *dragState
+var activeStageDrag *stageDragState
+
+/* ================================================================================ Private functions */
+func newDragLayer() *dragLayer {
+	layer := &dragLayer{
+		stageHighlight: canvas.NewRectangle(color.RGBA{255, 255, 255, 24}),
+		marker:         canvas.NewRectangle(theme.Color(theme.ColorNameFocus)),
+		preview:        canvas.NewRectangle(color.RGBA{0, 0, 0, 0}),
+		previewText:    canvas.NewText("", color.RGBA{0, 0, 0, 0}),
+	}
+	layer.previewText.TextStyle = fyne.TextStyle{Bold: true}
+	layer.container = container.NewWithoutLayout(layer.stageHighlight, layer.marker, layer.preview, layer.previewText)
+	layer.container.Hide()
+
+	return layer
+}
+
+func absolutePosition(object fyne.CanvasObject) fyne.Position {
+	return fyne.CurrentApp().Driver().AbsolutePositionForObject(object)
+}
+
+func startDrag(item *Item, grabOffset fyne.Position) {
+	activeDrag = &dragState{item: item, grabOffset: grabOffset, done: make(chan struct{})}
+
+	layer := board.dragLayer
+	layer.preview.FillColor = withAlpha(item.Style.Background, 192)
+	layer.preview.Resize(item.Size())
+	layer.previewText.Text = item.Title
+	layer.previewText.Color = item.Style.Foreground
+	layer.previewText.TextSize = GetScaledTextSize()
+	layer.container.Show()
+
+	/* Auto-scrolling has to go on while the pointer rests near an edge, when no drag events arrive */
+	go func(done chan struct{}) {
+		ticker := time.NewTicker(DRAG_AUTO_SCROLL_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				fyne.Do(autoScrollDrag)
+			case <-done:
+				return
+			}
+		}
+	}(activeDrag.done)
+}
+
+// updateDrag moves the preview to the pointer and updates the drop target feedback
+func updateDrag(pointer fyne.Position) {
+	if activeDrag == nil {
+		return
+	}
+	activeDrag.pointer = pointer
+
+	layer := board.dragLayer
+	boardPosition := absolutePosition(board)
+
+	previewPosition := pointer.Subtract(boardPosition).Subtract(activeDrag.grabOffset)
+	layer.preview.Move(previewPosition)
+	layer.previewText.Move(previewPosition.AddXY(theme.Padding(), theme.Padding()/2))
+
+	activeDrag.target = dropTargetAt(activeDrag.item, pointer)
+	if target := activeDrag.target; target != nil {
+		stagePosition := absolutePosition(target.stage).Subtract(boardPosition)
+		layer.stageHighlight.Move(stagePosition)
+		layer.stageHighlight.Resize(target.stage.Size())
+		layer.stageHighlight.Show()
+
+		markerHeight := theme.Padding() / 2
+		layer.marker.Move(fyne.NewPos(stagePosition.X+theme.Padding(), target.markerY-boardPosition.Y-markerHeight/2))
+		layer.marker.Resize(fyne.NewSize(target.stage.Size().Width-3*theme.Padding(), markerHeight))
+		layer.marker.Show()
+	} else {
+		layer.stageHighlight.Hide()
+		layer.marker.Hide()
+	}
+
+	layer.container.Refresh()
+}
+
+// autoScrollDrag scrolls the target stage while the pointer is near the top or bottom edge of its scroll area
+func autoScrollDrag() {
+	if activeDrag == nil || activeDrag.target == nil || activeDrag.target.stage.scrollArea == nil {
+		return
+	}
+
+	scrollArea := activeDrag.target.stage.scrollArea
+	top := absolutePosition(scrollArea).Y
+	bottom := top + scrollArea.Size().Height
+
+	step := float32(0)
+	switch {
+	case activeDrag.pointer.Y < top+DRAG_AUTO_SCROLL_EDGE:
+		step = -DRAG_AUTO_SCROLL_STEP
+	case activeDrag.pointer.Y > bottom-DRAG_AUTO_SCROLL_EDGE:
+		step = DRAG_AUTO_SCROLL_STEP
+	default:
+		return
+	}
+
+	offset := scrollArea.Offset
+	offset.Y = fyne.Max(0, fyne.Min(offset.Y+step, scrollArea.Content.Size().Height-scrollArea.Size().Height))
+	if offset.Y == scrollArea.Offset.Y {
+		return
+	}
+
+	activeDrag.target.stage.scrollTo(offset)
+	updateDrag(activeDrag.pointer)
+}
+
+// finishDrag removes the feedback and moves the existing item to the drop target, unless it would stay in place
+func finishDrag() {
+	if activeDrag == nil {
+		return
+	}
+	drag := activeDrag
+	activeDrag = nil
+
+	close(drag.done)
+	board.dragLayer.container.Hide()
+	board.dragLayer.container.Refresh()
+
+	target := dropTargetAt(drag.item, drag.pointer)
+	if target == nil {
+		return
+	}
+
+	source := board.ItemStage(drag.item)
+	if source == target.stage && board.ItemLane(drag.item.Item) == target.lane {
+		sourceIndex := source.ItemIndex(drag.item.Item)
+		if target.index == sourceIndex || (target.index < 0 && sourceIndex == len(source.Items)-1) {
+			return
+		}
+	}
+
+	move := func() {
+		board.Execute(&model.MoveItemCommand{Item: drag.item.Item, Target: target.stage.Stage, Index: target.index, Lane: target.lane})
+	}
+	if source == target.stage {
+		move()
+		return
+	}
+	target.stage.ConfirmWIPLimit(move)
+}
+
+// dropTargetAt finds the stage (and lane) under the absolute pointer position and the gap between its visible items
+// closest to it
+func dropTargetAt(dragged *Item, pointer fyne.Position) *dropTarget {
+	stage := board.StageAtPosition(pointer.Subtract(absolutePosition(board)))
+	if stage == nil || stage.scrollArea == nil {
+		return nil
+	}
+
+	source := board.ItemStage(dragged)
+	target := &dropTarget{stage: stage, index: -1}
+
+	top := absolutePosition(stage.scrollArea).Y
+	bottom := top + stage.scrollArea.Size().Height
+	target.markerY = top + theme.Padding()/2
+
+	items := stage.ItemWidgets()
+	if len(board.Lanes) > 0 {
+		section := laneSectionAt(stage, pointer)
+		if section == nil {
+			return nil
+		}
+		target.lane = section.Lane
+		target.markerY = absolutePosition(section).Y + section.MinSize().Height
+		items = section.ItemWidgets()
+		if section.Collapsed {
+			items = nil
+		}
+	}
+
+	var last *Item
+	for _, item := range items {
+		if !item.Visible() || item == dragged {
+			continue
+		}
+
+		itemTop := absolutePosition(item).Y
+		if pointer.Y < itemTop+item.Size().Height/2 {
+			target.index = stage.ItemIndex(item.Item)
+			target.markerY = itemTop - theme.Padding()/2
+			last = nil
+			break
+		}
+		target.markerY = itemTop + item.Size().Height + theme.Padding()/2
+		last = item
+	}
+
+	/* Behind the last item of a lane, the item is placed right after it, as items of other lanes may follow */
+	if last != nil && target.lane != nil {
+		target.index = stage.ItemIndex(last.Item) + 1
+	}
+
+	/* The index is counted without the dragged item, so account for its removal within the same stage */
+	if target.index >= 0 && source == stage && source.ItemIndex(dragged.Item) < target.index {
+		target.index--
+	}
+
+	target.markerY = fyne.Max(top, fyne.Min(target.markerY, bottom))
+	return target
+}
+
+// laneSectionAt finds the lane section of the stage at the absolute pointer position, the gap below a section belongs
+// to it
+func laneSectionAt(stage *Stage, pointer fyne.Position) *LaneSection {
+	var found *LaneSection
+
+	for _, section := range stage.LaneSections() {
+		if found == nil || pointer.Y >= absolutePosition(section).Y {
+			found = section
+		}
+	}
+	return found
+}
+
+func startStageDrag(stage *Stage, grabOffset fyne.Position) {
+	activeStageDrag = &stageDragState{stage: stage, grabOffset: grabOffset}
+
+	layer := board.dragLayer
+	layer.stageHighlight.Move(stage.Position())
+	layer.stageHighlight.Resize(stage.Size())
+	layer.stageHighlight.Show()
+	layer.preview.FillColor = color.RGBA{255, 255, 255, 48}
+	layer.preview.Resize(fyne.NewSize(stage.Size().Width, stage.scrollArea.Position().Y))
+	layer.previewText.Text = stage.Title
+	layer.previewText.Color = color.RGBA{255, 255, 255, 255}
+	layer.previewText.TextSize = GetScaledTextSubHeadingSize()
+	layer.container.Show()
+}
+
+// updateStageDrag moves the preview to the pointer and the marker to the gap between the stages closest to it
+func updateStageDrag(pointer fyne.Position) {
+	if activeStageDrag == nil {
+		return
+	}
+	activeStageDrag.pointer = pointer
+
+	layer := board.dragLayer
+	previewPosition := pointer.Subtract(absolutePosition(board)).Subtract(activeStageDrag.grabOffset)
+	layer.preview.Move(previewPosition)
+	layer.previewText.Move(previewPosition.AddXY(theme.Padding(), theme.Padding()/2))
+
+	target := stageDropTargetAt(activeStageDrag.stage, pointer)
+	markerWidth := theme.Padding() / 2
+	layer.marker.Move(fyne.NewPos(fyne.Max(0, fyne.Min(target.markerX-markerWidth/2, board.Size().Width-markerWidth)), 0))
+	layer.marker.Resize(fyne.NewSize(markerWidth, board.Size().Height))
+	layer.marker.Show()
+
+	layer.container.Refresh()
+}
+
+// finishStageDrag removes the feedback and moves the stage to the drop target, unless it would stay in place
+func finishStageDrag() {
+	if activeStageDrag == nil {
+		return
+	}
+	drag := activeStageDrag
+	activeStageDrag = nil
+
+	board.dragLayer.container.Hide()
+	board.dragLayer.container.Refresh()
+
+	target := stageDropTargetAt(drag.stage, drag.pointer)
+	sourceIndex := board.StageIndex(drag.stage.Stage)
+	if target.index == sourceIndex || (target.index < 0 && sourceIndex == len(board.Stages)-1) {
+		return
+	}
+
+	board.MoveStage(drag.stage, target.index)
+}
+
+// stageDropTargetAt finds the gap between the stages closest to the absolute pointer position
+func stageDropTargetAt(dragged *Stage, pointer fyne.Position) *stageDropTarget {
+	target := &stageDropTarget{index: -1}
+	x := pointer.X - absolutePosition(board).X
+
+	for _, stage := range board.StageWidgets() {
+		if stage == dragged {
+			continue
+		}
+
+		if x < stage.Position().X+stage.Size().Width/2 {
+			target.index = board.StageIndex(stage.Stage)
+			target.markerX = stage.Position().X
+
+			/* The index is counted without the dragged stage, so account for its removal */
+			if board.StageIndex(dragged.Stage) < target.index {
+				target.index--
+			}
+			break
+		}
+		target.markerX = stage.Position().X + stage.Size().Width
+	}
+
+	return target
+}
+
+func withAlpha(c color.RGBA, alpha uint8) color.NRGBA {
+	return color.NRGBA{c.R, c.G, c.B, alpha}
+}
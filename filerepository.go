@@ -0,0 +1,72 @@
+package main
+
+/* This file contains the storage repository for local files, which replaces the one of the driver so that the file
+   dialogs never truncate a file before anything is written to it - Fyne opens the writer as soon as a file is chosen */
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage/repository"
+)
+
+/* ================================================================================ Private types */
+// fileRepository is the set of repository interfaces the local file repository of the driver implements
+type fileRepository interface {
+	repository.AppendableRepository
+	repository.HierarchicalRepository
+	repository.ListableRepository
+	repository.CopyableRepository
+	repository.MovableRepository
+}
+
+// deferredFileRepository is the file repository of the driver, except that its writers only touch the file when
+// they are closed after writing, and then replace it atomically
+type deferredFileRepository struct {
+	fileRepository
+}
+
+type deferredFileWriter struct {
+	uri     fyne.URI
+	data    bytes.Buffer
+	written bool
+}
+
+/* ================================================================================ Public methods */
+func (r *deferredFileRepository) Writer(u fyne.URI) (fyne.URIWriteCloser, error) {
+	return &deferredFileWriter{uri: u}, nil
+}
+
+func (w *deferredFileWriter) URI() fyne.URI {
+	return w.uri
+}
+
+func (w *deferredFileWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.data.Write(p)
+}
+
+func (w *deferredFileWriter) Close() error {
+	if !w.written {
+		return nil
+	}
+	w.written = false
+
+	return model.WriteFileAtomic(w.uri.Path(), w.data.Bytes())
+}
+
+/* ================================================================================ Private functions */
+// registerDeferredFileRepository wraps the file repository registered by the driver, which has to exist already
+func registerDeferredFileRepository() {
+	registered, err := repository.ForScheme("file")
+	if err != nil {
+		return
+	}
+
+	if files, ok := registered.(fileRepository); ok {
+		repository.Register("file", &deferredFileRepository{files})
+	}
+}
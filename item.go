@@ -1,33 +1,28 @@
 package main
 
-/* Item is a draggable widget type describing an expandable entry inside a stage, which holds the actual task information */
+/* Item is a draggable widget type rendering an expandable entry of a stage model, which holds the actual task information */
 
 /* ================================================================================ Imports */
 import (
 	"image/color"
+	"strings"
+	"time"
+
+	"bankan/model"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
 /* ================================================================================ Public types */
-type ItemStyle struct {
-	Foreground, Background color.RGBA
-}
-
 type Item struct {
-	widget.BaseWidget `json:"-"`
-	Title             string
-	Description       string
-	Tags              []Tag
-	Style             ItemStyle
-	Expanded          bool
-	DataType          string        // 数据类型："Normal", "Gregorian", "Lunar", "Tibetan"
-	dragActive        bool          `json:"-"`
-	dragStartPosition fyne.Position `json:"-"`
-	dragEndPosition   fyne.Position `json:"-"`
+	widget.BaseWidget
+	*model.Item
+	board *Board
 }
 
 /* ================================================================================ Private types */
@@ -36,47 +31,121 @@ type itemRenderer struct {
 	titleLabel        *TappableCustomLabel
 	toolbarBackground *canvas.Circle
 	toolbar           *widget.Toolbar
+	dueLabel          *CustomLabel
+	progressBadge     *ProgressBadge
 	tagLabels         *[]*TappableCustomLabel
+	checklistLabels   *[]*TappableCustomLabel
 	descriptionLabel  *TappableCustomLabel
+	markdownView      *container.ThemeOverride
+	markdownText      *widget.RichText
+	markdownSource    *string
 	w                 *Item
 }
 
 /* ================================================================================ Public functions */
-func NewItem(title string, tags []Tag, description string, style ItemStyle, dataType string) *Item {
-	item := &Item{Title: title, Tags: tags, Description: description, Style: style, Expanded: false, DataType: dataType}
-	item.ExtendBaseWidget(item)
+func NewItem(board *Board, item *model.Item) *Item {
+	w := &Item{Item: item, board: board}
+	w.ExtendBaseWidget(w)
+
+	return w
+}
+
+/* ================================================================================ Private methods */
+// dueBadge returns the text and the color of the badge showing the start and due dates and the recurrence, the text is
+// empty without any of them
+func (w *Item) dueBadge() (string, color.RGBA) {
+	parts := []string{}
+	if !w.Start.IsZero() {
+		parts = append(parts, "Start "+model.FormatDate(w.Start))
+	}
+	if !w.Due.IsZero() {
+		if w.Due.Equal(model.Date(time.Now())) {
+			parts = append(parts, "Due today")
+		} else {
+			parts = append(parts, "Due "+model.FormatDate(w.Due))
+		}
+	}
+
+	if w.Recurrence != nil {
+		parts = append(parts, "↻ "+w.Recurrence.String())
+	}
 
-	return item
+	switch w.DueState(time.Now()) {
+	case model.DueOverdue:
+		return strings.Join(parts, " · "), ColorToRGBA(theme.Color(theme.ColorNameError))
+	case model.DueSoon:
+		return strings.Join(parts, " · "), ColorToRGBA(theme.Color(theme.ColorNameWarning))
+	default:
+		return strings.Join(parts, " · "), color.RGBA{96, 96, 96, 255}
+	}
 }
 
 /* ================================================================================ Public methods */
-func (w *Item) NewTagLabel(tag Tag) *TappableCustomLabel {
-	return NewTappableCustomLabel(fyne.TextAlignCenter, PaintStyle{w.Style.Background, w.Style.Foreground, color.RGBA{0, 0, 0, 0}, 1}, false, tag.DisplayString(), GetScaledCaptionTextSize(), fyne.TextStyle{Italic: true}, Paddings{0.0, 1.0, 1.0, 0.5}, Paddings{0.0, 0.0, 2.0, 2.0},
+// NewChecklistLabel returns the label of the checklist entry at the index, which ticks the entry off (or opens it
+// again) on tap
+func (w *Item) NewChecklistLabel(index int) *TappableCustomLabel {
+	return NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{w.Style.Foreground, color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, true, w.Checklist[index].String(), GetScaledTextSize(), fyne.TextStyle{Monospace: true}, Paddings{0.0, 0.0, 1.0, 0.5}, Paddings{0.0, 0.0, 0.0, 0.0},
 		func() {
-			board.ToggleFilterTag(tag)
+			w.board.Execute(&model.ToggleChecklistEntryCommand{Item: w.Item, Index: index})
 		},
 	)
 }
 
+func (w *Item) NewTagLabel(tag model.Tag) *TappableCustomLabel {
+	tagLabel := NewTappableCustomLabel(fyne.TextAlignCenter, PaintStyle{w.Style.Background, w.Style.Foreground, color.RGBA{0, 0, 0, 0}, 1}, false, tag.DisplayString(), GetScaledCaptionTextSize(), fyne.TextStyle{Italic: true}, Paddings{0.0, 1.0, 1.0, 0.5}, Paddings{0.0, 0.0, 2.0, 2.0},
+		func() {
+			w.board.ToggleFilterTag(tag)
+		},
+	)
+	tagLabel.Highlighter = w.searchHighlighter()
+
+	return tagLabel
+}
+
 func (w *Item) ShowEditItemDialog() {
-	ShowItemDialogWithDataType("Edit", w.Title, ComposeTagEditString(w.Tags), w.Description, w.Style, w.DataType,
-		func(title, tagEditString, description string, style ItemStyle, dataType string) {
-			w.Title = title
-			w.Tags = ParseTagEditString(tagEditString)
-			w.Description = description
-			w.Style = style
-			w.DataType = dataType
-			w.Refresh()
-			autoSave()
+	ShowItemDialogWithDataType("Edit", w.Title, model.ComposeTagEditString(w.Tags), w.Description, model.ComposeChecklistEditString(w.Checklist), w.Style, w.DataType, w.Start, w.Due,
+		func(title, tagEditString, description, checklistEditString string, style model.ItemStyle, dataType string, start, due time.Time) {
+			w.board.Execute(&model.EditItemCommand{Item: w.Item, Title: title, Tags: model.ParseTagEditString(tagEditString), Description: description, Style: style, DataType: dataType, Start: start, Due: due, Checklist: model.ParseChecklist(checklistEditString)})
 		},
 	)
 }
 
+// ShowRecurrenceDialog edits the schedule of the item and the stage its next occurrence is created in, once the item
+// is done (moved to the last stage)
+func (w *Item) ShowRecurrenceDialog() {
+	options := make([]string, len(w.board.Stages))
+	for i, stage := range w.board.Stages {
+		options[i] = stage.Title
+	}
+
+	selected := w.board.StageIndex(w.board.Board.ItemStage(w.Item))
+	if w.Recurrence != nil {
+		selected = w.board.StageIndex(w.board.RecurrenceStage(w.Item))
+	}
+
+	ShowEntrySelectDialog("Set Recurrence", "daily, weekly:mon,fri, monthly:1,15, lunar:1,15, tibetan:10,25 ...", w.Recurrence.String(), "Stage for next occurrence ...", options, selected,
+		func(text string, index int) {
+			recurrence, err := model.ParseRecurrence(text)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if recurrence != nil && index >= 0 {
+				recurrence.StageID = w.board.Stages[index].ID
+			}
+			w.board.Execute(&model.SetRecurrenceCommand{Item: w.Item, Recurrence: recurrence})
+		},
+	)
+}
+
+func (w *Item) ShowActivityDialog() {
+	ShowActivityDialog("Activity of "+w.Title, w.board.ItemActivities(w.ID))
+}
+
 func (w *Item) ShowRemoveItemConfirmDialog() {
 	ShowConfirmDialog("Remove Item", "This will remove the item from the board.\n\nAre you sure?\n",
 		func() {
-			board.RemoveItem(w)
-			autoSave()
+			w.board.RemoveItem(w)
 		},
 	)
 }
@@ -85,11 +154,13 @@ func (w *Item) ShowItemMenu() {
 	menu := widget.NewPopUpMenu(
 		fyne.NewMenu("Item",
 			fyne.NewMenuItem("Edit Item", w.ShowEditItemDialog),
+			fyne.NewMenuItem("Set Recurrence", w.ShowRecurrenceDialog),
+			fyne.NewMenuItem("Show Activity", w.ShowActivityDialog),
 			fyne.NewMenuItem("Remove Item", w.ShowRemoveItemConfirmDialog),
 		), window.Canvas(),
 	)
 
-	stage := board.ItemStage(w)
+	stage := w.board.ItemStage(w)
 	if stage == nil {
 		return
 	}
@@ -97,19 +168,22 @@ func (w *Item) ShowItemMenu() {
 	menu.ShowAtPosition(fyne.NewPos(stage.Position().X+w.Position().X+w.Size().Width+-menu.Size().Width-18, stage.Position().Y+w.Position().Y+menu.Size().Height+38))
 }
 
-func (w *Item) SetFilterTags(filterTags []Tag) {
-	match := false
+// showsMarkdown returns whether the description is rendered as Markdown, which is the case unless the board keeps plain
+// text or a search is active (the matches are only highlighted in plain text)
+func (w *Item) showsMarkdown() bool {
+	return !w.board.PlainText && w.board.Search.IsEmpty()
+}
 
-	for _, filterTag := range filterTags {
-		for _, tag := range w.Tags {
-			if tag == filterTag {
-				match = true
-				break
-			}
-		}
+// searchHighlighter returns the function to find the search matches to highlight, nil if there is no search
+func (w *Item) searchHighlighter() func(line string) [][]int {
+	if w.board.Search.IsEmpty() {
+		return nil
 	}
+	return w.board.Search.FindAll
+}
 
-	if match || len(filterTags) < 1 {
+func (w *Item) SetFilter(filter *model.Filter) {
+	if filter.Matches(w.Item) {
 		w.Show()
 	} else {
 		w.Hide()
@@ -120,51 +194,22 @@ func (w *Item) ToggleExpanded() {
 	w.Expanded = !w.Expanded
 	w.Refresh()
 	autoSave()
+
+	/* The height of the lane may change in all stages */
+	if len(w.board.Lanes) > 0 {
+		w.board.Refresh()
+	}
 }
 
 func (w *Item) Dragged(event *fyne.DragEvent) {
-	if !w.dragActive {
-		w.dragActive = true
-		w.dragStartPosition = event.Position
+	if activeDrag == nil {
+		startDrag(w, event.Position)
 	}
-	w.dragEndPosition = event.Position
+	updateDrag(event.AbsolutePosition)
 }
 
 func (w *Item) DragEnd() {
-	w.dragActive = false
-
-	itemRect := Rectangle{fyne.NewPos(w.Position().X, 0), w.Size()}
-	if !itemRect.Contains(w.dragStartPosition) || itemRect.Contains(w.dragEndPosition) {
-		return
-	}
-
-	sourceStage := board.ItemStage(w)
-	if sourceStage == nil {
-		return
-	}
-
-	boardRelativeEndPosition := fyne.NewPos(sourceStage.Position().X+w.Position().X+w.dragEndPosition.X, sourceStage.Position().Y+w.Position().Y+w.dragEndPosition.Y)
-	targetStage := board.StageAtPosition(boardRelativeEndPosition)
-	if targetStage == nil {
-		return
-	}
-
-	targetStageRelativeEndPosition := fyne.NewPos(boardRelativeEndPosition.X-targetStage.Position().X, boardRelativeEndPosition.Y-targetStage.Position().Y)
-	targetItem := targetStage.ItemAtPosition(targetStageRelativeEndPosition)
-
-	if targetItem != nil {
-		targetItemRelativeEndY := targetStageRelativeEndPosition.Y - targetItem.Position().Y
-		targetItemHeightMidY := (targetItem.Size().Height / 2)
-		if targetItemRelativeEndY < targetItemHeightMidY {
-			targetStage.InsertItemWithDataType(false, targetItem, w.Title, w.Tags, w.Description, w.Style, w.DataType)
-	} else {
-		targetStage.InsertItemWithDataType(true, targetItem, w.Title, w.Tags, w.Description, w.Style, w.DataType)
-	}
-} else {
-	targetStage.AppendItemWithDataType(w.Title, w.Tags, w.Description, w.Style, w.DataType)
-	}
-
-	sourceStage.RemoveItem(w)
+	finishDrag()
 }
 
 /* ================================================================================ Public rendering methods */
@@ -175,19 +220,71 @@ func (w *Item) CreateRenderer() fyne.WidgetRenderer {
 	titleLabel := NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{w.Style.Foreground, color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, true, w.Title, GetScaledTextSize(), fyne.TextStyle{Bold: true}, Paddings{0.0, 0.25, 1.0, 0.0}, Paddings{0.0, 0.0, 0.0, 0.0}, w.ToggleExpanded)
 	toolbarBackground := canvas.NewCircle(color.RGBA{0, 0, 0, 127})
 	toolbar := widget.NewToolbar(widget.NewToolbarAction(theme.MoreVerticalIcon(), w.ShowItemMenu))
+	dueText, dueColor := w.dueBadge()
+	dueLabel := NewCustomLabel(fyne.TextAlignCenter, PaintStyle{color.RGBA{255, 255, 255, 255}, dueColor, color.RGBA{0, 0, 0, 0}, 0}, false, dueText, GetScaledCaptionTextSize(), fyne.TextStyle{Bold: true}, Paddings{0.0, 1.0, 1.0, 0.5}, Paddings{0.0, 0.0, 2.0, 2.0})
+	if dueText == "" {
+		dueLabel.Hide()
+	}
+	done, total := w.ChecklistProgress()
+	progressBadge := NewProgressBadge(done, total, PaintStyle{w.Style.Background, w.Style.Foreground, color.RGBA{0, 0, 0, 0}, 0}, GetScaledCaptionTextSize(), Paddings{0.0, 1.0, 1.0, 0.5}, Paddings{0.0, 0.0, 2.0, 2.0})
+	if total == 0 {
+		progressBadge.Hide()
+	}
 	tagLabels := make([]*TappableCustomLabel, len(w.Tags))
 
 	for i, tag := range w.Tags {
 		tagLabels[i] = w.NewTagLabel(tag)
 	}
 
+	checklistLabels := make([]*TappableCustomLabel, len(w.Checklist))
+
+	for i := range w.Checklist {
+		checklistLabels[i] = w.NewChecklistLabel(i)
+		if !w.Expanded {
+			checklistLabels[i].Hide()
+		}
+	}
+
 	descriptionLabel := NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{w.Style.Foreground, color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, true, w.Description, GetScaledTextSize(), fyne.TextStyle{Monospace: true}, Paddings{0.0, 1.0, 1.0, 0.5}, Paddings{0.0, 0.0, 0.0, 0.0}, w.ToggleExpanded)
 
-	if !w.Expanded {
+	titleLabel.Highlighter = w.searchHighlighter()
+	descriptionLabel.Highlighter = w.searchHighlighter()
+
+	markdownView, markdownText := newMarkdownView(w.Description, w.Style.Foreground)
+	markdownSource := w.Description
+
+	if !w.Expanded || w.showsMarkdown() {
 		descriptionLabel.Hide()
 	}
+	if !w.Expanded || !w.showsMarkdown() {
+		markdownView.Hide()
+	}
 
-	return &itemRenderer{background, titleLabel, toolbarBackground, toolbar, &tagLabels, descriptionLabel, w}
+	return &itemRenderer{background, titleLabel, toolbarBackground, toolbar, dueLabel, progressBadge, &tagLabels, &checklistLabels, descriptionLabel, markdownView, markdownText, &markdownSource, w}
+}
+
+// descriptionView returns the shown view of the description, the rendered Markdown or the plain text label
+func (r itemRenderer) descriptionView() fyne.CanvasObject {
+	if r.w.showsMarkdown() {
+		return r.markdownView
+	}
+	return r.descriptionLabel
+}
+
+// flowLabels returns the labels flowing in lines below the title, the due badge and the checklist progress (if shown)
+// followed by the tags
+func (r itemRenderer) flowLabels() []fyne.CanvasObject {
+	labels := []fyne.CanvasObject{}
+	if r.dueLabel.Visible() {
+		labels = append(labels, r.dueLabel)
+	}
+	if r.progressBadge.Visible() {
+		labels = append(labels, r.progressBadge)
+	}
+	for _, tagLabel := range *r.tagLabels {
+		labels = append(labels, tagLabel)
+	}
+	return labels
 }
 
 func (r itemRenderer) Layout(size fyne.Size) {
@@ -213,7 +310,7 @@ func (r itemRenderer) Layout(size fyne.Size) {
 	tagsBlockHeight := float32(0)
 	tagsLineMaxHeight := float32(0)
 
-	for _, tagLabel := range *r.tagLabels {
+	for _, tagLabel := range r.flowLabels() {
 		tagSize := tagLabel.MinSize()
 
 		if tagsLineWidth > 0 && (tagsLineWidth+tagSize.Width) > size.Width {
@@ -230,8 +327,20 @@ func (r itemRenderer) Layout(size fyne.Size) {
 	}
 	tagsBlockHeight += tagsLineMaxHeight
 
-	r.descriptionLabel.Resize(fyne.NewSize(size.Width, size.Height-headerHeight-tagsBlockHeight))
-	r.descriptionLabel.Move(fyne.NewPos(0, headerHeight+tagsBlockHeight))
+	/* The checklist entries are wrapped to the item width, so their height is measured after the width is set */
+	checklistBlockHeight := float32(0)
+	if r.w.Expanded {
+		for _, checklistLabel := range *r.checklistLabels {
+			checklistLabel.Resize(fyne.NewSize(size.Width, checklistLabel.Size().Height))
+			checklistLabel.Resize(fyne.NewSize(size.Width, checklistLabel.MinSize().Height))
+			checklistLabel.Move(fyne.NewPos(0, headerHeight+tagsBlockHeight+checklistBlockHeight))
+			checklistBlockHeight += checklistLabel.Size().Height
+		}
+	}
+
+	descriptionView := r.descriptionView()
+	descriptionView.Resize(fyne.NewSize(size.Width, size.Height-headerHeight-tagsBlockHeight-checklistBlockHeight))
+	descriptionView.Move(fyne.NewPos(0, headerHeight+tagsBlockHeight+checklistBlockHeight))
 }
 
 func (r itemRenderer) MinSize() fyne.Size {
@@ -245,7 +354,7 @@ func (r itemRenderer) MinSize() fyne.Size {
 	tagsLineMaxWidth := float32(0)
 	tagsLineMaxHeight := float32(0)
 
-	for _, tagLabel := range *r.tagLabels {
+	for _, tagLabel := range r.flowLabels() {
 		tagSize := tagLabel.MinSize()
 
 		if tagsLineWidth > 0 && (tagsLineWidth+tagSize.Width) > maxWidth {
@@ -259,8 +368,13 @@ func (r itemRenderer) MinSize() fyne.Size {
 	}
 	tagsBlockHeight += tagsLineMaxHeight
 
-	descriptionSize := r.descriptionLabel.MinSize()
-	if !r.w.Expanded {
+	descriptionSize := r.descriptionView().MinSize()
+	checklistBlockHeight := float32(0)
+	if r.w.Expanded {
+		for _, checklistLabel := range *r.checklistLabels {
+			checklistBlockHeight += checklistLabel.MinSize().Height
+		}
+	} else {
 		descriptionSize.Height = 0
 	}
 
@@ -268,17 +382,30 @@ func (r itemRenderer) MinSize() fyne.Size {
 	minTitleWidth := float32(200) + toolbarWidth // 设置一个合理的最小宽度
 	// 不使用descriptionSize.Width，避免长内容撑开item宽度
 	minWidth := fyne.Max(tagsLineMaxWidth, minTitleWidth)
-	minHeight := headerHeight + tagsBlockHeight + descriptionSize.Height
+	minHeight := headerHeight + tagsBlockHeight + checklistBlockHeight + descriptionSize.Height
 
 	return fyne.NewSize(minWidth, Round(minHeight))
 }
 
 func (r itemRenderer) Refresh() {
 	r.background.FillColor = r.w.Style.Background
+	r.background.StrokeWidth = 0
+	switch {
+	case r.w.board.FocusedItem == r.w.Item:
+		r.background.StrokeColor = theme.Color(theme.ColorNameFocus)
+		r.background.StrokeWidth = theme.Padding() / 2
+	case r.w.board.SearchHit == r.w.Item:
+		/* Mark the current search hit */
+		r.background.StrokeColor = color.RGBA{255, 200, 0, 255}
+		r.background.StrokeWidth = theme.Padding() / 2
+	}
 	r.background.Refresh()
 
+	highlighter := r.w.searchHighlighter()
+
 	r.titleLabel.Style.Foreground = r.w.Style.Foreground
 	r.titleLabel.Text = r.w.Title
+	r.titleLabel.Highlighter = highlighter
 	r.titleLabel.Refresh()
 
 	tagLabelsCount := len(*r.tagLabels)
@@ -288,6 +415,7 @@ func (r itemRenderer) Refresh() {
 			(*r.tagLabels)[i].Style.Foreground = r.w.Style.Background
 			(*r.tagLabels)[i].Style.Background = r.w.Style.Foreground
 			(*r.tagLabels)[i].Text = tag.DisplayString()
+			(*r.tagLabels)[i].Highlighter = highlighter
 			(*r.tagLabels)[i].Refresh()
 		} else {
 			*r.tagLabels = append(*r.tagLabels, r.w.NewTagLabel(tag))
@@ -296,30 +424,91 @@ func (r itemRenderer) Refresh() {
 
 	*r.tagLabels = (*r.tagLabels)[:len(r.w.Tags)]
 
+	dueText, dueColor := r.w.dueBadge()
+	r.dueLabel.Text = dueText
+	r.dueLabel.Style.Background = dueColor
+	if dueText == "" {
+		r.dueLabel.Hide()
+	} else {
+		r.dueLabel.Show()
+	}
+	r.dueLabel.Refresh()
+
+	done, total := r.w.ChecklistProgress()
+	r.progressBadge.Done = done
+	r.progressBadge.Total = total
+	r.progressBadge.Style.Foreground = r.w.Style.Background
+	r.progressBadge.Style.Background = r.w.Style.Foreground
+	if total == 0 {
+		r.progressBadge.Hide()
+	} else {
+		r.progressBadge.Show()
+	}
+	r.progressBadge.Refresh()
+
+	checklistLabelsCount := len(*r.checklistLabels)
+
+	for i, entry := range r.w.Checklist {
+		if i >= checklistLabelsCount {
+			*r.checklistLabels = append(*r.checklistLabels, r.w.NewChecklistLabel(i))
+		}
+		checklistLabel := (*r.checklistLabels)[i]
+		checklistLabel.Style.Foreground = r.w.Style.Foreground
+		checklistLabel.Text = entry.String()
+		checklistLabel.Highlighter = highlighter
+		if r.w.Expanded {
+			checklistLabel.Show()
+		} else {
+			checklistLabel.Hide()
+		}
+		checklistLabel.Refresh()
+	}
+
+	*r.checklistLabels = (*r.checklistLabels)[:len(r.w.Checklist)]
+
 	r.descriptionLabel.Style.Foreground = r.w.Style.Foreground
 	r.descriptionLabel.Text = r.w.Description
+	r.descriptionLabel.Highlighter = highlighter
 	r.descriptionLabel.Refresh()
 
-	if r.w.Expanded {
+	if *r.markdownSource != r.w.Description {
+		*r.markdownSource = r.w.Description
+		r.markdownText.ParseMarkdown(r.w.Description)
+	}
+	setMarkdownForeground(r.markdownView, r.w.Style.Foreground)
+
+	if r.w.Expanded && !r.w.showsMarkdown() {
 		r.descriptionLabel.Show()
 	} else {
 		r.descriptionLabel.Hide()
 	}
+	if r.w.Expanded && r.w.showsMarkdown() {
+		r.markdownView.Show()
+	} else {
+		r.markdownView.Hide()
+	}
 }
 
 func (r itemRenderer) Objects() []fyne.CanvasObject {
-	objectCount := len(*r.tagLabels) + 5
+	objectCount := len(*r.tagLabels) + len(*r.checklistLabels) + 8
 	objects := make([]fyne.CanvasObject, objectCount)
 	objects[0] = r.background
 	objects[1] = r.titleLabel
 	objects[2] = r.toolbarBackground
 	objects[3] = r.toolbar
+	objects[4] = r.dueLabel
+	objects[5] = r.progressBadge
 
 	for i, tagLabel := range *r.tagLabels {
-		objects[i+4] = tagLabel
+		objects[i+6] = tagLabel
+	}
+
+	for i, checklistLabel := range *r.checklistLabels {
+		objects[i+6+len(*r.tagLabels)] = checklistLabel
 	}
 
-	objects[objectCount-1] = r.descriptionLabel
+	objects[objectCount-2] = r.descriptionLabel
+	objects[objectCount-1] = r.markdownView
 
 	return objects
 }
@@ -0,0 +1,246 @@
+package main
+
+/* This file contains the keyboard navigation over the stages and items of the board:
+
+   Arrow keys         move the focus between the items of a stage and across stages
+   Ctrl+arrow keys    move the focused item within its stage or to the neighbour stage,
+                      or move the focused stage to the left or right
+   Enter              expand/collapse the focused item
+   e                  edit the focused item, or the title of the focused stage
+   n                  create a new item in the focused stage
+   m                  show the menu of the focused item or stage
+   Delete             remove the focused item, or the focused stage
+   Escape             clear the focus */
+
+/* ================================================================================ Imports */
+import (
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+/* ================================================================================ Private functions */
+func registerKeyboardShortcuts(canvas fyne.Canvas) {
+	canvas.SetOnTypedKey(keyTyped)
+	canvas.SetOnTypedRune(runeTyped)
+
+	moves := map[fyne.KeyName]func(){
+		fyne.KeyUp:    func() { moveFocusedItem(0, -1) },
+		fyne.KeyDown:  func() { moveFocusedItem(0, 1) },
+		fyne.KeyLeft:  func() { moveFocusedItem(-1, 0) },
+		fyne.KeyRight: func() { moveFocusedItem(1, 0) },
+	}
+	for key, move := range moves {
+		canvas.AddShortcut(&desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) { move() })
+	}
+}
+
+func keyTyped(event *fyne.KeyEvent) {
+	switch event.Name {
+	case fyne.KeyUp:
+		moveFocus(0, -1)
+	case fyne.KeyDown:
+		moveFocus(0, 1)
+	case fyne.KeyLeft:
+		moveFocus(-1, 0)
+	case fyne.KeyRight:
+		moveFocus(1, 0)
+
+	case fyne.KeyReturn, fyne.KeyEnter:
+		if item, _ := focusedWidgets(); item != nil {
+			item.ToggleExpanded()
+		}
+
+	case fyne.KeyDelete:
+		item, stage := focusedWidgets()
+		switch {
+		case item != nil:
+			item.ShowRemoveItemConfirmDialog()
+		case stage != nil:
+			stage.ShowRemoveStageConfirmDialog()
+		}
+
+	case fyne.KeyEscape:
+		board.SetFocus(nil, nil)
+	}
+}
+
+func runeTyped(r rune) {
+	item, stage := focusedWidgets()
+
+	switch r {
+	case 'e':
+		switch {
+		case item != nil:
+			item.ShowEditItemDialog()
+		case stage != nil:
+			stage.ShowEditStageTitleDialog()
+		}
+
+	case 'n':
+		if stage != nil {
+			stage.ShowCreateItemDialog()
+		}
+
+	case 'm':
+		switch {
+		case item != nil:
+			item.ShowItemMenu()
+		case stage != nil:
+			stage.ShowStageMenu()
+		}
+	}
+}
+
+// focusedWidgets returns the widgets of the focused item and stage, after dropping a focus on removed objects
+func focusedWidgets() (*Item, *Stage) {
+	syncKeyboardFocus()
+
+	if board.FocusedItem != nil {
+		return board.ItemWidget(board.FocusedItem)
+	}
+	for _, stage := range board.StageWidgets() {
+		if stage.Stage == board.FocusedStage {
+			return nil, stage
+		}
+	}
+	return nil, nil
+}
+
+// syncKeyboardFocus moves the focus from removed or hidden items to their stage and drops it from removed stages
+func syncKeyboardFocus() {
+	if board.FocusedItem != nil {
+		stage := board.Board.ItemStage(board.FocusedItem)
+		if stage == nil || !board.IsItemShown(board.FocusedItem) {
+			board.FocusedItem = nil
+		} else {
+			board.FocusedStage = stage
+		}
+	}
+
+	if board.FocusedStage != nil && board.StageIndex(board.FocusedStage) < 0 {
+		board.FocusedStage = nil
+	}
+}
+
+// visibleItems returns the shown items of the stage in display order, which is lane by lane if the board has lanes
+func visibleItems(stage *model.Stage) []*model.Item {
+	if len(board.Lanes) < 1 {
+		return stage.FilterItems(board.Filter)
+	}
+
+	items := []*model.Item{}
+	for _, lane := range board.Lanes {
+		for _, item := range board.LaneItems(stage, lane) {
+			if board.IsItemShown(item) {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// moveFocus moves the focus by the given number of stages and items, starting at the first stage if nothing has the
+// focus yet, the position inside the stage is kept when moving to another stage as good as possible
+func moveFocus(stageStep, itemStep int) {
+	syncKeyboardFocus()
+	if len(board.Stages) < 1 {
+		return
+	}
+
+	stageIndex := board.StageIndex(board.FocusedStage)
+	if stageIndex < 0 {
+		stage := board.Stages[0]
+		items := visibleItems(stage)
+		if len(items) > 0 {
+			board.SetFocus(stage, items[0])
+		} else {
+			board.SetFocus(stage, nil)
+		}
+		return
+	}
+
+	items := visibleItems(board.FocusedStage)
+	itemIndex := -1
+	for i, item := range items {
+		if item == board.FocusedItem {
+			itemIndex = i
+		}
+	}
+
+	stageIndex = min(max(stageIndex+stageStep, 0), len(board.Stages)-1)
+	stage := board.Stages[stageIndex]
+	if stage != board.FocusedStage {
+		items = visibleItems(stage)
+	}
+
+	if len(items) < 1 {
+		board.SetFocus(stage, nil)
+		return
+	}
+
+	itemIndex = min(max(itemIndex+itemStep, 0), len(items)-1)
+	board.SetFocus(stage, items[itemIndex])
+}
+
+// moveFocusedItem moves the focused item by the given number of stages or positions among the visible items, if only a
+// stage has the focus, the stage itself is moved by the number of stages
+func moveFocusedItem(stageStep, itemStep int) {
+	item, stage := focusedWidgets()
+	if item == nil {
+		if stage != nil && stage.MoveBy(stageStep) {
+			board.SetFocus(stage.Stage, nil)
+		}
+		return
+	}
+
+	items := visibleItems(stage.Stage)
+	visibleIndex := -1
+	for i, visible := range items {
+		if visible == item.Item {
+			visibleIndex = i
+		}
+	}
+
+	targetIndex := board.StageIndex(stage.Stage) + stageStep
+	if targetIndex < 0 || targetIndex >= len(board.Stages) {
+		return
+	}
+	target := board.Stages[targetIndex]
+
+	/* The index is counted without the moved item, so the index of the visible neighbour is right in both directions:
+	   above the item it stays the same, below the item it shrinks by one, which places the item after the neighbour -
+	   the item joins the lane of the neighbour, so it can be moved across lanes as well */
+	index := -1
+	lane := board.ItemLane(item.Item)
+	if target == stage.Stage {
+		neighbourIndex := visibleIndex + itemStep
+		if neighbourIndex < 0 || neighbourIndex >= len(items) {
+			return
+		}
+		index = target.ItemIndex(items[neighbourIndex])
+		lane = board.ItemLane(items[neighbourIndex])
+	} else if targetItems := visibleItems(target); visibleIndex < len(targetItems) {
+		index = target.ItemIndex(targetItems[visibleIndex])
+		lane = board.ItemLane(targetItems[visibleIndex])
+	}
+
+	var targetStage *Stage
+	for _, stageWidget := range board.StageWidgets() {
+		if stageWidget.Stage == target {
+			targetStage = stageWidget
+		}
+	}
+
+	move := func() {
+		if board.Execute(&model.MoveItemCommand{Item: item.Item, Target: target, Index: index, Lane: lane}) {
+			board.SetFocus(target, item.Item)
+		}
+	}
+	if target == stage.Stage {
+		move()
+		return
+	}
+	targetStage.ConfirmWIPLimit(move)
+}
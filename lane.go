@@ -0,0 +1,237 @@
+package main
+
+/* LaneSection is a widget type rendering the part of a swimlane inside one stage: a header, which collapses the lane
+   on tap, followed by the item widgets of the stage assigned to the lane - the sections of a lane get the same height
+   in all stages, so the lanes line up across the board */
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"image/color"
+
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+/* ================================================================================ Public types */
+type LaneSection struct {
+	widget.BaseWidget
+	*model.Lane
+	stage *Stage
+	items []*Item
+}
+
+/* ================================================================================ Private types */
+type laneSectionRenderer struct {
+	background    *canvas.Rectangle
+	titleLabel    *TappableCustomLabel
+	toolbar       *widget.Toolbar
+	itemContainer *fyne.Container
+	w             *LaneSection
+}
+
+// laneLayout stacks the lane sections of a stage, each with the largest height of the sections of its lane on the board
+type laneLayout struct{}
+
+/* ================================================================================ Public functions */
+func NewLaneSection(lane *model.Lane, stage *Stage) *LaneSection {
+	w := &LaneSection{Lane: lane, stage: stage}
+	w.ExtendBaseWidget(w)
+
+	return w
+}
+
+/* ================================================================================ Private methods */
+func (w *LaneSection) titleText() string {
+	marker := "▾"
+	if w.Collapsed {
+		marker = "▸"
+	}
+	return fmt.Sprintf("%s %s (%d)", marker, w.Title, len(w.items))
+}
+
+// laneHeight returns the largest height of the sections of the lane in all stages
+func (w *LaneSection) laneHeight() float32 {
+	height := float32(0)
+
+	for _, stage := range w.stage.board.StageWidgets() {
+		if section := stage.laneSection(w.Lane); section != nil {
+			height = fyne.Max(height, section.MinSize().Height)
+		}
+	}
+	return height
+}
+
+/* ================================================================================ Public methods */
+func (w *LaneSection) ItemWidgets() []*Item {
+	return w.items
+}
+
+func (w *LaneSection) ToggleCollapsed() {
+	w.Collapsed = !w.Collapsed
+	w.stage.board.Refresh()
+	autoSave()
+}
+
+func (w *LaneSection) ShowEditLaneTitleDialog() {
+	ShowEntryDialog("Edit Swimlane Title", "Title ...", w.Title,
+		func(text string) {
+			w.stage.board.Execute(&model.RenameLaneCommand{Lane: w.Lane, Title: text})
+		},
+	)
+}
+
+func (w *LaneSection) ShowRemoveLaneConfirmDialog() {
+	ShowConfirmDialog("Remove Swimlane", "This will remove the swimlane, its items are moved to the first remaining swimlane.\n\nAre you sure?\n",
+		func() {
+			w.stage.board.RemoveLane(w.Lane)
+		},
+	)
+}
+
+// MoveBy moves the lane by the given number of positions down, or up if negative
+func (w *LaneSection) MoveBy(step int) bool {
+	index := w.stage.board.LaneIndex(w.Lane) + step
+	if index < 0 || index >= len(w.stage.board.Lanes) || step == 0 {
+		return false
+	}
+
+	return w.stage.board.Execute(&model.MoveLaneCommand{Lane: w.Lane, Index: index})
+}
+
+func (w *LaneSection) ShowLaneMenu() {
+	index := w.stage.board.LaneIndex(w.Lane)
+
+	moveUp := fyne.NewMenuItem("Move Swimlane Up", func() { w.MoveBy(-1) })
+	moveUp.Disabled = index <= 0
+	moveDown := fyne.NewMenuItem("Move Swimlane Down", func() { w.MoveBy(1) })
+	moveDown.Disabled = index >= len(w.stage.board.Lanes)-1
+
+	menu := widget.NewPopUpMenu(
+		fyne.NewMenu("Swimlane",
+			fyne.NewMenuItem("Edit Swimlane Title", w.ShowEditLaneTitleDialog),
+			moveUp,
+			moveDown,
+			fyne.NewMenuItem("Remove Swimlane", w.ShowRemoveLaneConfirmDialog),
+		), window.Canvas(),
+	)
+	menu.ShowAtPosition(absolutePosition(w).AddXY(w.Size().Width-menu.Size().Width-30, 10))
+}
+
+/* ================================================================================ Public rendering methods */
+func (w *LaneSection) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+
+	background := canvas.NewRectangle(color.RGBA{255, 255, 255, 24})
+	titleLabel := NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, false, w.titleText(), GetScaledTextSize(), fyne.TextStyle{Bold: true}, Paddings{0.5, 0.5, 1.0, 1.0}, Paddings{0.0, 0.0, 0.0, 0.0}, w.ToggleCollapsed)
+	toolbar := widget.NewToolbar(
+		widget.NewToolbarAction(theme.ContentAddIcon(), func() { w.stage.ShowCreateItemInLaneDialog(w.Lane) }),
+		widget.NewToolbarAction(theme.MoreVerticalIcon(), w.ShowLaneMenu),
+	)
+
+	r := &laneSectionRenderer{background, titleLabel, toolbar, container.NewVBox(), w}
+	r.Refresh()
+
+	return r
+}
+
+func (r laneSectionRenderer) headerHeight() float32 {
+	return fyne.Max(r.titleLabel.MinSize().Height, r.toolbar.MinSize().Height)
+}
+
+func (r laneSectionRenderer) Layout(size fyne.Size) {
+	headerHeight := r.headerHeight()
+	toolbarSize := r.toolbar.MinSize()
+
+	r.background.Resize(fyne.NewSize(size.Width, headerHeight))
+	r.background.Move(fyne.NewPos(0, 0))
+
+	r.titleLabel.Resize(fyne.NewSize(size.Width-toolbarSize.Width, headerHeight))
+	r.titleLabel.Move(fyne.NewPos(0, 0))
+
+	r.toolbar.Resize(fyne.NewSize(toolbarSize.Width, headerHeight))
+	r.toolbar.Move(fyne.NewPos(size.Width-toolbarSize.Width, 0))
+
+	r.itemContainer.Resize(fyne.NewSize(size.Width, size.Height-headerHeight-theme.Padding()))
+	r.itemContainer.Move(fyne.NewPos(0, headerHeight+theme.Padding()))
+}
+
+func (r laneSectionRenderer) MinSize() fyne.Size {
+	titleSize := r.titleLabel.MinSize()
+	toolbarSize := r.toolbar.MinSize()
+
+	minWidth := titleSize.Width + toolbarSize.Width
+	minHeight := r.headerHeight()
+	if !r.w.Collapsed {
+		containerSize := r.itemContainer.MinSize()
+		minWidth = fyne.Max(minWidth, containerSize.Width)
+		minHeight += theme.Padding() + containerSize.Height
+	}
+
+	return fyne.NewSize(minWidth, minHeight)
+}
+
+func (r laneSectionRenderer) Refresh() {
+	r.titleLabel.Text = r.w.titleText()
+	r.titleLabel.Refresh()
+
+	/* The lane menu is only offered once, in the section of the first stage */
+	moreAction := r.toolbar.Items[1].ToolbarObject()
+	if r.w.stage.board.StageIndex(r.w.stage.Stage) == 0 {
+		moreAction.Show()
+	} else {
+		moreAction.Hide()
+	}
+	r.toolbar.Refresh()
+
+	r.itemContainer.RemoveAll()
+	for _, item := range r.w.items {
+		r.itemContainer.Add(item)
+		item.Refresh()
+	}
+
+	if r.w.Collapsed {
+		r.itemContainer.Hide()
+	} else {
+		r.itemContainer.Show()
+	}
+}
+
+func (r laneSectionRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.background, r.titleLabel, r.toolbar, r.itemContainer}
+}
+
+func (r laneSectionRenderer) Destroy() {
+}
+
+func (l laneLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	y := float32(0)
+
+	for _, object := range objects {
+		section := object.(*LaneSection)
+		height := section.laneHeight()
+
+		section.Resize(fyne.NewSize(size.Width, height))
+		section.Move(fyne.NewPos(0, y))
+		y += height + theme.Padding()
+	}
+}
+
+func (l laneLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	minSize := fyne.NewSize(0, 0)
+
+	for i, object := range objects {
+		section := object.(*LaneSection)
+		if i > 0 {
+			minSize.Height += theme.Padding()
+		}
+		minSize.Width = fyne.Max(minSize.Width, section.MinSize().Width)
+		minSize.Height += section.laneHeight()
+	}
+	return minSize
+}
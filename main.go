@@ -7,13 +7,21 @@ import (
 	"fmt"
 	"image/color"
 	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"bankan/model"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
@@ -22,19 +30,36 @@ import (
 
 /* ================================================================================ Constants */
 const (
-	WINDOW_TITLE = "BanKan"
+	APP_ID               = "de.bananajoh.bankan"
+	WINDOW_TITLE         = "BanKan"
+	DEFAULT_BACKUP_COUNT = 5
+)
+
+const (
+	SAVE_STATUS_SAVED = iota
+	SAVE_STATUS_UNSAVED
+	SAVE_STATUS_FAILED
 )
 
 /* ================================================================================ Private variables */
 var window fyne.Window
 var board *Board
 var boardToolbar *widget.Toolbar
+var undoAction *widget.ToolbarAction
+var redoAction *widget.ToolbarAction
 var filterBinding binding.String
+var filterErrorLabel *widget.Label
 var boardNameLabel *CustomLabel
 var saveFileURI fyne.URI
+var backupCount int
+var saveStatus int
+var saveStatusButton *widget.Button
 
 /* ================================================================================ Private functions */
 func setSaveFileURI(uri fyne.URI) {
+	if uri == nil || saveFileURI == nil || uri.String() != saveFileURI.String() {
+		forgetSaveFileState()
+	}
 	saveFileURI = uri
 	windowTitleSuffix := ""
 
@@ -59,14 +84,71 @@ func restorePreferences() {
 		}
 	}
 
+	backupCount = fyne.CurrentApp().Preferences().IntWithFallback("backupCount", DEFAULT_BACKUP_COUNT)
+
 	// 恢复字体大小设置
 	RestoreFontSizeLevel()
 }
 
+func setBackupCount(count int) {
+	backupCount = count
+	fyne.CurrentApp().Preferences().SetInt("backupCount", count)
+}
+
 func autoSave() {
 	if saveFileURI != nil {
-		saveBoardURI(board, saveFileURI)
+		saveBoard(saveFileURI, false)
+	} else if len(board.Stages) > 0 {
+		setSaveStatus(SAVE_STATUS_UNSAVED)
+	}
+}
+
+// saveBoard saves the board and updates the save status, errors of interactive saves are always shown, errors of
+// automatic saves only when the previous save succeeded, to not flood the user with dialogs on every change
+func saveBoard(uri fyne.URI, interactive bool) {
+	/* Never silently clobber changes made to the file by someone else */
+	if saveFileURI != nil && uri.String() == saveFileURI.String() && saveFileChangedExternally() {
+		setSaveStatus(SAVE_STATUS_UNSAVED)
+		if interactive || !saveFileChangeIgnored() {
+			showExternalChangeDialog()
+		}
+		return
+	}
+
+	err := saveBoardURI(board, uri)
+	reportSaveResult(uri, err, interactive)
+}
+
+func reportSaveResult(uri fyne.URI, err error, interactive bool) {
+	if err == nil {
+		setSaveStatus(SAVE_STATUS_SAVED)
+		return
+	}
+
+	if interactive || saveStatus != SAVE_STATUS_FAILED {
+		dialog.ShowError(fmt.Errorf("Could not save the board to %s:\n\n%w", uri.Path(), err), window)
+	}
+	setSaveStatus(SAVE_STATUS_FAILED)
+}
+
+func setSaveStatus(status int) {
+	saveStatus = status
+
+	switch status {
+	case SAVE_STATUS_UNSAVED:
+		saveStatusButton.SetText("Unsaved changes")
+		saveStatusButton.SetIcon(theme.WarningIcon())
+		saveStatusButton.Importance = widget.WarningImportance
+		saveStatusButton.Show()
+	case SAVE_STATUS_FAILED:
+		saveStatusButton.SetText("Save failed - Retry")
+		saveStatusButton.SetIcon(theme.ErrorIcon())
+		saveStatusButton.Importance = widget.DangerImportance
+		saveStatusButton.Show()
+	default:
+		saveStatusButton.Hide()
 	}
+	saveStatusButton.Refresh()
 }
 
 func windowCloseInterceptor() {
@@ -74,72 +156,173 @@ func windowCloseInterceptor() {
 }
 
 func clearBoard() {
-	board.Clear()
-
-	board.Name = "New Board"
-	syncBoardNameLabel()
-
+	/* Detach from the save file first, so the cleared board does not overwrite it */
 	setSaveFileURI(nil)
+
+	board.Execute(&model.ClearBoardCommand{Name: "New Board"})
 }
 
-func loadBoardReader(board *Board, reader fyne.URIReadCloser) {
+func loadBoardReader(board *Board, reader fyne.URIReadCloser) error {
 	data, err := io.ReadAll(reader)
+	closeErr := reader.Close()
+
 	if err != nil {
-		fmt.Println(err)
+		return err
 	}
-
-	if err := reader.Close(); err != nil {
-		fmt.Println(err)
-		return
+	if closeErr != nil {
+		return closeErr
 	}
 
-	board.Clear()
-
-	if err := board.Load(data); err != nil {
-		fmt.Println(err)
-		return
+	/* Loading the save file again replaces the board by another version of it, which keeps the activity log */
+	load := board.Load
+	if saveFileURI != nil && reader.URI().String() == saveFileURI.String() {
+		load = board.Reload
+	}
+	if err := load(data); err != nil {
+		return err
 	}
-
-	syncBoardNameLabel()
 
 	setSaveFileURI(reader.URI())
+	rememberSaveFileState(data)
+	setSaveStatus(SAVE_STATUS_SAVED)
+
+	return nil
 }
 
-func loadBoardURI(board *Board, uri fyne.URI) {
-	if reader, err := storage.Reader(uri); reader != nil && err == nil {
-		loadBoardReader(board, reader)
+func loadBoardURI(board *Board, uri fyne.URI) error {
+	reader, err := storage.Reader(uri)
+	if err != nil {
+		return err
 	}
+
+	return loadBoardReader(board, reader)
 }
 
 func loadBoardSaveFile() {
-	if saveFileURI != nil {
-		loadBoardURI(board, saveFileURI)
+	if saveFileURI == nil {
+		return
 	}
+
+	if err := loadBoardURI(board, saveFileURI); err != nil {
+		showLoadError(saveFileURI, err)
+
+		/* Never let the auto save overwrite a file that could not be loaded, e.g. because a newer version wrote it */
+		setSaveFileURI(nil)
+	}
+}
+
+func showLoadError(uri fyne.URI, err error) {
+	dialog.ShowError(fmt.Errorf("Could not load the board from %s:\n\n%w", uri.Path(), err), window)
 }
 
-func saveBoardWriter(board *Board, writer fyne.URIWriteCloser) {
+func saveBoardWriter(board *Board, writer fyne.URIWriteCloser) error {
+	/* Local files are saved straight by path with backup, the writer of the file dialog has not touched them yet */
+	if writer.URI().Scheme() == "file" {
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		return saveBoardURI(board, writer.URI())
+	}
+
 	data, err := board.Data()
 	if err != nil {
-		fmt.Println(err)
-		return
+		writer.Close()
+		return err
 	}
 
-	if written, err := writer.Write(data); err != nil || written != len(data) {
-		fmt.Println(err)
+	written, err := writer.Write(data)
+	if err == nil && written != len(data) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		writer.Close()
+		return err
 	}
 
 	if err = writer.Close(); err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
 	setSaveFileURI(writer.URI())
+
+	return nil
 }
 
-func saveBoardURI(board *Board, uri fyne.URI) {
-	if writer, err := storage.Writer(uri); writer != nil && err == nil {
-		saveBoardWriter(board, writer)
+func saveBoardURI(board *Board, uri fyne.URI) error {
+	if uri.Scheme() == "file" {
+		if err := model.SaveFile(board.Board, uri.Path(), backupCount); err != nil {
+			return err
+		}
+		setSaveFileURI(uri)
+
+		data, _ := board.Data()
+		rememberSaveFileState(data)
+		return nil
+	}
+
+	writer, err := storage.Writer(uri)
+	if err != nil {
+		return err
 	}
+
+	return saveBoardWriter(board, writer)
+}
+
+func restoreBackup(backupPath string) {
+	restored := &model.Board{}
+	data, err := os.ReadFile(backupPath)
+	if err == nil {
+		err = restored.Load(data)
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("Could not restore the backup %s:\n\n%w", backupPath, err), window)
+		return
+	}
+
+	/* Restoring is a command, so it can be undone like any other change, the backups may not hold the replaced content */
+	board.Execute(&model.ReplaceBoardCommand{Board: restored, Action: model.ActivityBackupRestored})
+}
+
+func showRestoreBackupDialog() {
+	if saveFileURI == nil || saveFileURI.Scheme() != "file" {
+		dialog.ShowInformation("Restore from Backup", "Backups are only available for boards saved to a local file.", window)
+		return
+	}
+
+	backupPaths, err := model.BackupPaths(saveFileURI.Path())
+	if err != nil || len(backupPaths) < 1 {
+		dialog.ShowInformation("Restore from Backup", "There are no backups of this board yet.", window)
+		return
+	}
+
+	options := make([]string, len(backupPaths))
+	for i, backupPath := range backupPaths {
+		options[i] = filepath.Base(backupPath)
+		if backupTime, err := model.BackupTime(backupPath); err == nil {
+			options[i] = backupTime.Format("2006-01-02 15:04:05")
+		}
+	}
+
+	ShowSelectDialog("Restore from Backup", "Backup ...", options,
+		func(index int) {
+			ShowConfirmDialog("Restore from Backup", "This will replace the current board by the backup from "+options[index]+".\n\nAre you sure?\n",
+				func() { restoreBackup(backupPaths[index]) },
+			)
+		},
+	)
+}
+
+func showBackupCountDialog() {
+	ShowEntryDialog("Backup Generations", "Number of backups to keep ...", strconv.Itoa(backupCount),
+		func(text string) {
+			count, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil || count < 0 {
+				dialog.ShowError(fmt.Errorf("%q is not a valid number of backups", text), window)
+				return
+			}
+			setBackupCount(count)
+		},
+	)
 }
 
 func syncBoardNameLabel() {
@@ -147,23 +330,170 @@ func syncBoardNameLabel() {
 	boardNameLabel.Refresh()
 }
 
+func syncHistoryActions() {
+	if board.History.CanUndo() {
+		undoAction.Enable()
+	} else {
+		undoAction.Disable()
+	}
+
+	if board.History.CanRedo() {
+		redoAction.Enable()
+	} else {
+		redoAction.Disable()
+	}
+}
+
+func boardChanged() {
+	syncBoardNameLabel()
+	syncHistoryActions()
+	syncSearchHits()
+	syncViewSelect()
+}
+
+func undoShortcut(shortcut fyne.Shortcut) {
+	board.Undo()
+}
+
+func redoShortcut(shortcut fyne.Shortcut) {
+	board.Redo()
+}
+
 func newButtonTapped() {
 	ShowConfirmDialog("Create Empty Board", "This will discard the current board.\n\nAre you sure?\n", clearBoard)
 }
 
 func loadButtonTapped() {
 	ShowFileOpenConfirmDialog("Load Board", "This will discard the current board.\n\nAre you sure?\n", saveFileURI,
-		func(reader fyne.URIReadCloser) { loadBoardReader(board, reader) },
+		func(reader fyne.URIReadCloser) {
+			if err := loadBoardReader(board, reader); err != nil {
+				showLoadError(reader.URI(), err)
+				return
+			}
+			restoreFilterView()
+		},
+	)
+}
+
+func showImportDialog() {
+	ShowFileOpenConfirmDialog("Import Board", "This will add the stages and items of the file (BanKan, Trello, my-personal-kanban or\nKanbanapp JSON) to the current board.\n\nAre you sure?\n", saveFileURI,
+		func(reader fyne.URIReadCloser) {
+			report, err := importBoardReader(reader)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Could not import the board from %s:\n\n%w", reader.URI().Path(), err), window)
+				return
+			}
+			dialog.ShowInformation("Import Board", report.String(), window)
+		},
 	)
 }
 
+// importBoardReader adds the board of the reader to a copy of the current board, which replaces it in one undoable step
+func importBoardReader(reader fyne.URIReadCloser) (*model.ImportReport, error) {
+	data, err := io.ReadAll(reader)
+	closeErr := reader.Close()
+
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+
+	imported, report, err := model.Import(data, "")
+	if err != nil {
+		return nil, err
+	}
+
+	currentData, err := board.Data()
+	if err != nil {
+		return nil, err
+	}
+	target := &model.Board{}
+	if err := target.Load(currentData); err != nil {
+		return nil, err
+	}
+
+	importBoard(target, imported)
+	board.Execute(&model.ReplaceBoardCommand{Board: target, Action: model.ActivityImported})
+
+	return report, nil
+}
+
 func saveAsButtonTapped() {
-	ShowSaveAsDialog(saveFileURI, func(writer fyne.URIWriteCloser) { saveBoardWriter(board, writer) })
+	ShowSaveAsDialog(saveFileURI,
+		func(writer fyne.URIWriteCloser) {
+			err := saveBoardWriter(board, writer)
+			reportSaveResult(writer.URI(), err, true)
+		},
+	)
+}
+
+func exportButtonTapped() {
+	formats := model.ExportFormats()[1:]
+	options := []string{"Markdown document", "CSV table (one row per item)", "HTML page", "PNG image", "PDF document"}
+
+	ShowSelectDialog("Export Board", "Format ...", options,
+		func(index int) {
+			switch index {
+			case len(formats):
+				showSnapshotDialog(SnapshotPNG)
+				return
+			case len(formats) + 1:
+				showSnapshotDialog(SnapshotPDF)
+				return
+			}
+
+			ShowExportDialog(saveFileURI, formats[index].Extension(),
+				func(writer fyne.URIWriteCloser) {
+					data, err := board.Export(formats[index])
+					if err == nil {
+						_, err = writer.Write(data)
+					}
+
+					/* Local files are only written on close */
+					if closeErr := writer.Close(); err == nil {
+						err = closeErr
+					}
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("Could not export the board to %s:\n\n%w", writer.URI().Path(), err), window)
+					}
+				},
+			)
+		},
+	)
+}
+
+// showSnapshotDialog asks for the width of the snapshot, the current width of the board by default
+func showSnapshotDialog(format SnapshotFormat) {
+	ShowEntryDialog("Export Board", "Width in pixels ...", strconv.Itoa(int(board.Size().Width)),
+		func(text string) {
+			width, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil || width < SNAPSHOT_MIN_WIDTH {
+				dialog.ShowError(fmt.Errorf("%q is not a valid width, it must be at least %d pixels", text, SNAPSHOT_MIN_WIDTH), window)
+				return
+			}
+
+			ShowExportDialog(saveFileURI, format.Extension(),
+				func(writer fyne.URIWriteCloser) {
+					err := WriteBoardSnapshot(writer, board.Board, float32(width), format)
+
+					/* Local files are only written on close */
+					if closeErr := writer.Close(); err == nil {
+						err = closeErr
+					}
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("Could not export the board to %s:\n\n%w", writer.URI().Path(), err), window)
+					}
+				},
+			)
+		},
+	)
 }
 
 func saveButtonTapped() {
 	if saveFileURI != nil {
-		saveBoardURI(board, saveFileURI)
+		saveBoard(saveFileURI, true)
 	} else {
 		saveAsButtonTapped()
 	}
@@ -172,17 +502,29 @@ func saveButtonTapped() {
 func showEditBoardNameDialog() {
 	ShowEntryDialog("Edit Board Name", "Name ...", board.Name,
 		func(text string) {
-			board.Name = text
-			syncBoardNameLabel()
-			autoSave()
+			board.Rename(text)
 		},
 	)
 }
 
 func showBoardMenu() {
+	plainTextItem := fyne.NewMenuItem("Plain Text Descriptions", func() { board.Execute(&model.SetPlainTextCommand{PlainText: !board.PlainText}) })
+	plainTextItem.Checked = board.PlainText
+
 	menu := widget.NewPopUpMenu(
 		fyne.NewMenu("Board", 
 			fyne.NewMenuItem("Edit Board Name", showEditBoardNameDialog),
+			fyne.NewMenuItem("New Swimlane", board.ShowCreateLaneDialog),
+			fyne.NewMenuItem("Sort All Stages by Due Date", func() { board.Execute(&model.SortByDueCommand{Stages: slices.Clone(board.Stages)}) }),
+			plainTextItem,
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Save Filter as View", showSaveViewDialog),
+			fyne.NewMenuItem("Remove Filter View", showRemoveViewDialog),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Activity Log", func() { ShowActivityDialog("Activity Log", board.Activities) }),
+			fyne.NewMenuItem("Import Board", showImportDialog),
+			fyne.NewMenuItem("Restore from Backup", showRestoreBackupDialog),
+			fyne.NewMenuItem("Backup Generations: "+strconv.Itoa(backupCount), showBackupCountDialog),
 			fyne.NewMenuItemSeparator(),
 			fyne.NewMenuItem("字体大小: "+GetCurrentFontSizeLevelName(), showFontSizeMenu),
 		),
@@ -211,16 +553,31 @@ func setFontSize(level int) {
 	window.Content().Refresh()
 }
 
-func boardFilterChanged(tagEditString string) {
-	filterBinding.Set(tagEditString)
+func boardFilterChanged(query string) {
+	filterBinding.Set(query)
 }
 
 func filterBindingChanged() {
-	if text, err := filterBinding.Get(); err == nil {
-		board.SetTagFilter(text)
+	text, err := filterBinding.Get()
+	if err != nil {
+		return
+	}
+
+	if err := board.SetTagFilter(text); err != nil {
+		filterErrorLabel.SetText("Filter: " + err.Error())
+		filterErrorLabel.Show()
+	} else {
+		filterErrorLabel.Hide()
+		syncSearchHits()
+		syncViewSelect()
 	}
 }
 
+func validateFilter(query string) error {
+	_, err := model.ParseFilter(query)
+	return err
+}
+
 func updateDateItems() {
 	// 更新所有日期类型的item
 	for _, stage := range board.Stages {
@@ -233,14 +590,14 @@ func updateDateItems() {
 				}
 
 				// 更新日期标签
-				updatedTags := []Tag{}
+				updatedTags := []model.Tag{}
 				dataTypeFound := false
 
 				for _, tag := range item.Tags {
 					if strings.HasPrefix(tag.Expression, item.DataType+"=") {
 						// 更新现有的日期标签
 						newDateString := getCurrentDateString(item.DataType)
-						updatedTags = append(updatedTags, Tag{Expression: item.DataType + "=" + newDateString})
+						updatedTags = append(updatedTags, model.Tag{Expression: item.DataType + "=" + newDateString})
 						dataTypeFound = true
 					} else {
 						updatedTags = append(updatedTags, tag)
@@ -251,14 +608,22 @@ func updateDateItems() {
 				if dataTypeFound {
 					item.Tags = updatedTags
 				}
-				
-				item.Refresh()
 			}
 		}
 	}
+	board.Refresh()
 	autoSave()
 }
 
+// createOccurrences creates the next occurrences of the done recurring items, which are due until today
+func createOccurrences() {
+	today := time.Now()
+	items := board.DueRecurrences(today, getRecurrenceCalendars())
+	if len(items) > 0 {
+		board.Execute(&model.CreateOccurrencesCommand{Items: items, Today: today, Calendars: getRecurrenceCalendars()})
+	}
+}
+
 func startDateUpdateTimer() {
 	go func() {
 		for {
@@ -270,18 +635,25 @@ func startDateUpdateTimer() {
 			// 等待到凌晨
 			time.Sleep(duration)
 
-			// 更新日期item
-			updateDateItems()
-
-			// 每24小时重复一次
-			time.Sleep(24 * time.Hour)
+			// 更新日期item, the due badges and a filter comparing with "today" are evaluated anew as well, and recurring items
+			// get their next occurrence
+			fyne.Do(func() {
+				createOccurrences()
+				updateDateItems()
+				filterBindingChanged()
+			})
 		}
 	}()
 }
 
 func main() {
-	application := app.NewWithID("de.bananajoh.bankan")
+	if len(os.Args) > 1 {
+		os.Exit(runCommandLine(os.Args[1:]))
+	}
+
+	application := app.NewWithID(APP_ID)
 	application.SetIcon(theme.FyneLogo())
+	registerDeferredFileRepository()
 
 	window = application.NewWindow(WINDOW_TITLE)
 	window.SetCloseIntercept(windowCloseInterceptor)
@@ -295,35 +667,64 @@ func main() {
 		widget.NewToolbarAction(theme.FolderOpenIcon(), loadButtonTapped),
 		widget.NewToolbarAction(theme.DownloadIcon(), saveAsButtonTapped),
 		widget.NewToolbarAction(theme.DocumentSaveIcon(), saveButtonTapped),
+		widget.NewToolbarAction(theme.UploadIcon(), exportButtonTapped),
 	)
+	filterErrorLabel = widget.NewLabel("")
+	filterErrorLabel.Importance = widget.DangerImportance
+	filterErrorLabel.Hide()
+
 	filterBinding = binding.NewString()
 	filterBinding.AddListener(binding.NewDataListener(filterBindingChanged))
 
 	filterEntry := widget.NewEntryWithData(filterBinding)
-	filterEntry.SetPlaceHolder("Filter by Tag ...")
+	filterEntry.SetPlaceHolder("Filter by Tag ... (a=b AND NOT c, x>=2)")
+	filterEntry.Validator = validateFilter
 
-	leftHeaderContainer := container.NewGridWithColumns(2, fileToolbar, filterEntry)
+	filterContainer := container.NewBorder(nil, nil, nil, newViewSelect(), filterEntry)
+	leftHeaderContainer := container.NewGridWithColumns(2, fileToolbar, filterContainer)
 
 	boardNameLabel = NewCustomLabel(fyne.TextAlignCenter, PaintStyle{color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, false, board.Name, GetScaledTextSubHeadingSize(), fyne.TextStyle{}, Paddings{1.0, 1.0, 1.0, 1.0}, Paddings{0.0, 0.0, 0.0, 0.0})
-	boardNameContainer := container.NewHBox(layout.NewSpacer(), boardNameLabel, layout.NewSpacer())
+	saveStatusButton = widget.NewButton("", saveButtonTapped)
+	saveStatusButton.Hide()
+	boardNameContainer := container.NewHBox(layout.NewSpacer(), boardNameLabel, saveStatusButton, layout.NewSpacer())
+
+	undoAction = widget.NewToolbarAction(theme.ContentUndoIcon(), board.Undo)
+	redoAction = widget.NewToolbarAction(theme.ContentRedoIcon(), board.Redo)
 
 	boardToolbar = widget.NewToolbar(
+		undoAction,
+		redoAction,
+		widget.NewToolbarSeparator(),
+		widget.NewToolbarAction(theme.SearchIcon(), toggleSearchBar),
 		widget.NewToolbarAction(theme.FolderNewIcon(), board.ShowCreateStageDialog),
 		widget.NewToolbarAction(theme.MoreVerticalIcon(), showBoardMenu),
 	)
 
 	toolbarContainer := container.NewBorder(nil, nil, leftHeaderContainer, boardToolbar, boardNameContainer)
-	headerBarContainer := container.NewVBox(toolbarContainer, widget.NewSeparator())
+	headerBarContainer := container.NewVBox(toolbarContainer, filterErrorLabel, newSearchBar(), widget.NewSeparator())
 	windowContainer := container.NewBorder(headerBarContainer, nil, nil, nil, board)
 
+	board.OnChanged = boardChanged
+	syncHistoryActions()
+
+	window.Canvas().AddShortcut(&fyne.ShortcutUndo{}, undoShortcut)
+	window.Canvas().AddShortcut(&fyne.ShortcutRedo{}, redoShortcut)
+	window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}, redoShortcut)
+	window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierShortcutDefault}, searchShortcut)
+	registerKeyboardShortcuts(window.Canvas())
+
 	loadBoardSaveFile()
+	restoreFilterView()
 
 	// 启动时立即更新日期item
+	createOccurrences()
 	updateDateItems()
 
 	// 启动日期更新定时器
 	startDateUpdateTimer()
 
+	startSaveFileWatcher()
+
 	window.SetContent(windowContainer)
 	window.Resize(fyne.NewSize(1200, 700))
 	window.CenterOnScreen()
@@ -0,0 +1,58 @@
+package main
+
+/* This file contains the Markdown rendering of item descriptions, which uses the rich text widget of Fyne (parsing
+   with goldmark) themed with the colors of the item, so the text stays readable on any item background */
+
+/* ================================================================================ Imports */
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+/* ================================================================================ Private types */
+// markdownTheme renders rich text in the foreground color and with the text size of the item descriptions
+type markdownTheme struct {
+	fyne.Theme
+	foreground color.Color
+	textSize   float32
+}
+
+/* ================================================================================ Private methods */
+func (t markdownTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch name {
+	case theme.ColorNameForeground, theme.ColorNameHyperlink:
+		return t.foreground
+	default:
+		return t.Theme.Color(name, variant)
+	}
+}
+
+func (t markdownTheme) Size(name fyne.ThemeSizeName) float32 {
+	switch name {
+	case theme.SizeNameText:
+		return t.textSize
+	case theme.SizeNameInnerPadding, theme.SizeNameLineSpacing:
+		return t.Theme.Size(name) / 2
+	default:
+		return t.Theme.Size(name)
+	}
+}
+
+/* ================================================================================ Private functions */
+// newMarkdownView returns the rendered Markdown text in the foreground color, links open in the browser on tap
+func newMarkdownView(text string, foreground color.Color) (*container.ThemeOverride, *widget.RichText) {
+	richText := widget.NewRichTextFromMarkdown(text)
+	richText.Wrapping = fyne.TextWrapWord
+
+	return container.NewThemeOverride(richText, markdownTheme{theme.Current(), foreground, GetScaledTextSize()}), richText
+}
+
+// setMarkdownForeground changes the foreground color of the rendered Markdown text
+func setMarkdownForeground(view *container.ThemeOverride, foreground color.Color) {
+	view.Theme = markdownTheme{theme.Current(), foreground, GetScaledTextSize()}
+	view.Refresh()
+}
@@ -0,0 +1,168 @@
+package model
+
+/* Activity is the headless type describing an entry of the append-only activity log of a board, which records who
+   created, edited, moved or removed an item and when - the log is written by the item commands and kept when the whole
+   board is replaced by another version of it, an undo is logged as a change of its own, so the log tells the whole
+   story, only a new empty board starts a new log */
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+)
+
+/* ================================================================================ Constants */
+const (
+	ACTIVITY_TIME_FORMAT = "2006-01-02 15:04"
+)
+
+/* ================================================================================ Public variables */
+// ActivityAuthor is the name logged as author of the activities, the name of the user account by default
+var ActivityAuthor = defaultActivityAuthor()
+
+/* ================================================================================ Public types */
+type ActivityAction string
+
+const (
+	ActivityCreated ActivityAction = "created"
+	ActivityEdited  ActivityAction = "edited"
+	ActivityMoved   ActivityAction = "moved"
+	ActivityRemoved ActivityAction = "removed"
+
+	/* Actions on the whole board, without item */
+	ActivityImported       ActivityAction = "imported"
+	ActivityMerged         ActivityAction = "merged"
+	ActivityReplaced       ActivityAction = "replaced"
+	ActivityRestored       ActivityAction = "restored"
+	ActivityBackupRestored ActivityAction = "restored backup"
+)
+
+type Activity struct {
+	ID        string
+	Time      time.Time
+	Author    string
+	Action    ActivityAction
+	ItemID    string
+	ItemTitle string        // title of the item at the time, so entries of removed items stay readable
+	FromStage string        // title of the stage the item was removed or moved from
+	ToStage   string        // title of the stage the item was created in or moved to
+	Changes   []FieldChange // changed fields of an edit
+}
+
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+/* ================================================================================ Public functions */
+// ItemChanges returns the changes of the logged fields from the item before to the item after
+func ItemChanges(before, after *Item) []FieldChange {
+	changes := []FieldChange{}
+
+	add := func(field, old, new string) {
+		if old != new {
+			changes = append(changes, FieldChange{field, old, new})
+		}
+	}
+	add("Title", before.Title, after.Title)
+	add("Tags", strings.TrimSuffix(ComposeTagEditString(before.Tags), "; "), strings.TrimSuffix(ComposeTagEditString(after.Tags), "; "))
+	add("Description", before.Description, after.Description)
+	add("Style", styleString(before.Style), styleString(after.Style))
+	add("Data type", before.DataType, after.DataType)
+
+	return changes
+}
+
+/* ================================================================================ Public methods */
+// LogActivity appends the activity to the log with a new ID, the current time and the author
+func (b *Board) LogActivity(activity Activity) {
+	activity.ID = NewID()
+	activity.Time = time.Now()
+	activity.Author = ActivityAuthor
+	b.Activities = append(b.Activities, activity)
+}
+
+// ItemActivities returns the logged activities of the item, oldest first
+func (b *Board) ItemActivities(itemID string) []Activity {
+	activities := []Activity{}
+	for _, activity := range b.Activities {
+		if activity.ItemID == itemID {
+			activities = append(activities, activity)
+		}
+	}
+	return activities
+}
+
+// Summary returns what happened in one line, e.g. `moved "Fix bug" from "Todo" to "Done"`
+func (a Activity) Summary() string {
+	switch a.Action {
+	case ActivityCreated:
+		return fmt.Sprintf("created %q in %q", a.ItemTitle, a.ToStage)
+	case ActivityMoved:
+		return fmt.Sprintf("moved %q from %q to %q", a.ItemTitle, a.FromStage, a.ToStage)
+	case ActivityRemoved:
+		return fmt.Sprintf("removed %q from %q", a.ItemTitle, a.FromStage)
+	case ActivityImported:
+		return "imported a board"
+	case ActivityMerged:
+		return "merged the changes of the file"
+	case ActivityReplaced:
+		return "replaced the board"
+	case ActivityRestored:
+		return "restored the board as before"
+	case ActivityBackupRestored:
+		return "restored the board from a backup"
+	default:
+		fields := make([]string, len(a.Changes))
+		for i, change := range a.Changes {
+			fields[i] = strings.ToLower(change.Field)
+		}
+		return fmt.Sprintf("edited %s of %q", strings.Join(fields, ", "), a.ItemTitle)
+	}
+}
+
+// String returns the time, author and summary followed by the field changes on separate lines
+func (a Activity) String() string {
+	lines := []string{fmt.Sprintf("%s  %s %s", a.Time.Local().Format(ACTIVITY_TIME_FORMAT), a.Author, a.Summary())}
+	for _, change := range a.Changes {
+		lines = append(lines, "    "+change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %q → %q", c.Field, c.Old, c.New)
+}
+
+/* ================================================================================ Private methods */
+func (b *Board) logItemActivity(action ActivityAction, item *Item, from, to *Stage, changes []FieldChange) {
+	activity := Activity{Action: action, ItemID: item.ID, ItemTitle: item.Title, Changes: changes}
+	if from != nil {
+		activity.FromStage = from.Title
+	}
+	if to != nil {
+		activity.ToStage = to.Title
+	}
+	b.LogActivity(activity)
+}
+
+/* ================================================================================ Private functions */
+func defaultActivityAuthor() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	for _, name := range []string{"USER", "USERNAME"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return "unknown"
+}
+
+func styleString(style ItemStyle) string {
+	return hexColor(style.Foreground) + " on " + hexColor(style.Background)
+}
@@ -0,0 +1,109 @@
+package model
+
+/* This file contains the tests of the activity log written by the board commands */
+
+/* ================================================================================ Imports */
+import (
+	"slices"
+	"testing"
+)
+
+/* ================================================================================ Public functions */
+func TestBoardCommandActivities(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  func(b *Board) Command
+		done     []ActivityAction
+		undone   []ActivityAction
+		layout   string
+		restored string
+	}{
+		{"clear", func(b *Board) Command { return &ClearBoardCommand{Name: "New Board"} },
+			[]ActivityAction{},
+			[]ActivityAction{ActivityCreated, ActivityRestored},
+			"", "Todo: A, B | Done: C"},
+		{"replace", func(b *Board) Command { return &ReplaceBoardCommand{Board: &Board{Stages: []*Stage{{Title: "New"}}}} },
+			[]ActivityAction{ActivityCreated, ActivityReplaced},
+			[]ActivityAction{ActivityCreated, ActivityReplaced, ActivityRestored},
+			"New: ", "Todo: A, B | Done: C"},
+		{"merge", func(b *Board) Command { return &ReplaceBoardCommand{Board: mergeTestBoard(), Action: ActivityMerged} },
+			[]ActivityAction{ActivityCreated, ActivityMerged},
+			[]ActivityAction{ActivityCreated, ActivityMerged, ActivityRestored},
+			"Todo: A, B | Done: C", "Todo: A, B | Done: C"},
+		{"restore backup", func(b *Board) Command {
+			return &ReplaceBoardCommand{Board: mergeTestBoard(), Action: ActivityBackupRestored}
+		},
+			[]ActivityAction{ActivityCreated, ActivityBackupRestored},
+			[]ActivityAction{ActivityCreated, ActivityBackupRestored, ActivityRestored},
+			"Todo: A, B | Done: C", "Todo: A, B | Done: C"},
+		{"remove stage", func(b *Board) Command { return &RemoveStageCommand{Stage: b.StageByID("todo")} },
+			[]ActivityAction{ActivityCreated, ActivityRemoved, ActivityRemoved},
+			[]ActivityAction{ActivityCreated, ActivityRemoved, ActivityRemoved, ActivityCreated, ActivityCreated},
+			"Done: C", "Todo: A, B | Done: C"},
+	}
+
+	for _, test := range tests {
+		b := mergeTestBoard()
+		b.logItemActivity(ActivityCreated, b.ItemByID("a"), nil, b.StageByID("todo"), nil)
+		command := test.command(b)
+
+		if err := command.Do(b); err != nil {
+			t.Fatalf("%s: Do() failed: %v", test.name, err)
+		}
+		if got := activityActions(b); !slices.Equal(got, test.done) {
+			t.Errorf("%s: logged %v, want %v", test.name, got, test.done)
+		}
+		if got := testBoardLayout(b); got != test.layout {
+			t.Errorf("%s: board = %q, want %q", test.name, got, test.layout)
+		}
+
+		if err := command.Undo(b); err != nil {
+			t.Fatalf("%s: Undo() failed: %v", test.name, err)
+		}
+		if got := activityActions(b); !slices.Equal(got, test.undone) {
+			t.Errorf("%s: logged %v after undo, want %v", test.name, got, test.undone)
+		}
+		if got := testBoardLayout(b); got != test.restored {
+			t.Errorf("%s: board = %q after undo, want %q", test.name, got, test.restored)
+		}
+	}
+}
+
+func TestBoardReloadKeepsActivities(t *testing.T) {
+	saved := mergeTestBoard()
+	saved.logItemActivity(ActivityCreated, saved.ItemByID("a"), nil, saved.StageByID("todo"), nil)
+	data, _ := saved.Data()
+
+	b := &Board{}
+	if err := b.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	b.logItemActivity(ActivityCreated, b.ItemByID("b"), nil, b.StageByID("todo"), nil)
+
+	if err := b.Reload(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := activityActions(b); !slices.Equal(got, []ActivityAction{ActivityCreated, ActivityCreated}) {
+		t.Errorf("logged %v after reload, want both creations", got)
+	}
+
+	if err := b.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := activityActions(b); !slices.Equal(got, []ActivityAction{ActivityCreated}) {
+		t.Errorf("logged %v after load, want the log of the file", got)
+	}
+
+	if err := b.Reload([]byte("no json")); err == nil || len(b.Activities) != 1 {
+		t.Errorf("failed reload changed the log to %v", activityActions(b))
+	}
+}
+
+/* ================================================================================ Private functions */
+func activityActions(b *Board) []ActivityAction {
+	actions := []ActivityAction{}
+	for _, activity := range b.Activities {
+		actions = append(actions, activity.Action)
+	}
+	return actions
+}
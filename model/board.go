@@ -0,0 +1,212 @@
+package model
+
+/* Board is the headless top-level type describing a kanban board, which contains and manages stages and the rules to
+   change them - it has no dependency on the GUI toolkit, so it can be used by scripts and tests as well as the widgets */
+
+/* ================================================================================ Imports */
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+/* ================================================================================ Public variables */
+var ErrStageNotFound = errors.New("stage not found on board")
+var ErrItemNotFound = errors.New("item not found on board")
+var ErrViewNotFound = errors.New("view not found on board")
+var ErrLaneNotFound = errors.New("lane not found on board")
+var ErrWIPLimitExceeded = errors.New("WIP limit of the stage exceeded")
+
+/* ================================================================================ Public types */
+type Board struct {
+	Version    int
+	Name       string
+	PlainText  bool // item descriptions are shown as plain text instead of rendered Markdown
+	Views      []FilterView
+	Lanes      []*Lane
+	Stages     []*Stage
+	Activities []Activity // append-only log of the item changes
+}
+
+/* ================================================================================ Public functions */
+func NewBoard(name string) *Board {
+	return &Board{Name: name}
+}
+
+/* ================================================================================ Public methods */
+func (b *Board) Clear() {
+	b.Stages = b.Stages[:0]
+}
+
+func (b *Board) Data() ([]byte, error) {
+	b.Version = SCHEMA_VERSION
+	return json.Marshal(b)
+}
+
+// Load replaces the whole board content by the given JSON data after migrating it to the current schema version, the board is left untouched on error
+func (b *Board) Load(data []byte) error {
+	data, err := Migrate(data)
+	if err != nil {
+		return err
+	}
+
+	loaded := Board{}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return err
+	}
+	*b = loaded
+
+	return nil
+}
+
+// Reload replaces the board content by the given JSON data of another version of the same board, like Load, but keeps
+// the activity log joined with the one of the data
+func (b *Board) Reload(data []byte) error {
+	activities := b.Activities
+	if err := b.Load(data); err != nil {
+		return err
+	}
+	b.Activities = mergeActivities(activities, b.Activities)
+
+	return nil
+}
+
+func (b *Board) StageIndex(toFind *Stage) int {
+	for i, stage := range b.Stages {
+		if stage == toFind {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *Board) ItemStageIndex(toFind *Item) int {
+	for i, stage := range b.Stages {
+		if stage.ItemIndex(toFind) >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *Board) ItemStage(toFind *Item) *Stage {
+	i := b.ItemStageIndex(toFind)
+	if i < 0 {
+		return nil
+	}
+
+	return b.Stages[i]
+}
+
+func (b *Board) StageByID(id string) *Stage {
+	for _, stage := range b.Stages {
+		if stage.ID == id {
+			return stage
+		}
+	}
+	return nil
+}
+
+func (b *Board) ItemByID(id string) *Item {
+	for _, stage := range b.Stages {
+		if item := stage.ItemByID(id); item != nil {
+			return item
+		}
+	}
+	return nil
+}
+
+func (b *Board) StageByTitle(title string) *Stage {
+	for _, stage := range b.Stages {
+		if stage.Title == title {
+			return stage
+		}
+	}
+	return nil
+}
+
+func (b *Board) AppendStage(stage *Stage) {
+	b.Stages = append(b.Stages, stage)
+}
+
+// InsertStage inserts the stage before the given index, an index out of range appends it
+func (b *Board) InsertStage(index int, stage *Stage) {
+	if index < 0 || index >= len(b.Stages) {
+		b.AppendStage(stage)
+		return
+	}
+
+	b.Stages = append(b.Stages, nil)
+	copy(b.Stages[index+1:], b.Stages[index:])
+	b.Stages[index] = stage
+}
+
+func (b *Board) RemoveStage(toRemove *Stage) bool {
+	i := b.StageIndex(toRemove)
+	if i < 0 {
+		return false
+	}
+
+	b.Stages = append(b.Stages[:i], b.Stages[i+1:]...)
+
+	return true
+}
+
+// MoveStage moves the stage before the given index (counted without the stage itself), an index out of range appends it
+func (b *Board) MoveStage(stage *Stage, index int) error {
+	if !b.RemoveStage(stage) {
+		return ErrStageNotFound
+	}
+
+	b.InsertStage(index, stage)
+	return nil
+}
+
+func (b *Board) RemoveItem(toRemove *Item) bool {
+	for _, stage := range b.Stages {
+		if stage.RemoveItem(toRemove) {
+			return true
+		}
+	}
+	return false
+}
+
+// MoveItem moves the item to the target stage before the given index (counted without the item itself), an index out of range appends it
+func (b *Board) MoveItem(item *Item, target *Stage, index int) error {
+	source := b.ItemStage(item)
+	if source == nil {
+		return ErrItemNotFound
+	}
+	if b.StageIndex(target) < 0 {
+		return ErrStageNotFound
+	}
+
+	source.RemoveItem(item)
+	target.InsertItem(index, item)
+	item.Moved = time.Now()
+
+	return nil
+}
+
+func (b *Board) FilterItems(filter *Filter) []*Item {
+	items := []*Item{}
+
+	for _, stage := range b.Stages {
+		items = append(items, stage.FilterItems(filter)...)
+	}
+	return items
+}
+
+// SearchItems returns the items matching the search in board order, stage by stage from top to bottom
+func (b *Board) SearchItems(search *Search) []*Item {
+	items := []*Item{}
+
+	for _, stage := range b.Stages {
+		for _, item := range stage.Items {
+			if search.Matches(item) {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
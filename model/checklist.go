@@ -0,0 +1,92 @@
+package model
+
+/* ChecklistEntry is the headless type describing a subtask of an item, which can be ticked off - checklists are
+   edited as text with one entry per line, "[x] " marks done entries and "[ ] " open ones */
+
+/* ================================================================================ Imports */
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+/* ================================================================================ Public variables */
+var ErrChecklistEntryNotFound = errors.New("checklist entry not found")
+
+/* ================================================================================ Public types */
+type ChecklistEntry struct {
+	Text string
+	Done bool
+}
+
+/* ================================================================================ Public functions */
+// ParseChecklist parses the checklist edit text, empty lines are skipped and lines without box are open entries
+func ParseChecklist(text string) []ChecklistEntry {
+	entries := []ChecklistEntry{}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		entry := ChecklistEntry{Text: line}
+
+		switch {
+		case strings.HasPrefix(line, "[x]"), strings.HasPrefix(line, "[X]"):
+			entry = ChecklistEntry{Text: strings.TrimSpace(line[3:]), Done: true}
+		case strings.HasPrefix(line, "[ ]"):
+			entry = ChecklistEntry{Text: strings.TrimSpace(line[3:])}
+		case strings.HasPrefix(line, "[]"):
+			entry = ChecklistEntry{Text: strings.TrimSpace(line[2:])}
+		}
+
+		if entry.Text != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// ComposeChecklistEditString returns the edit text of the checklist, which is read by ParseChecklist
+func ComposeChecklistEditString(entries []ChecklistEntry) string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+/* ================================================================================ Public methods */
+func (e ChecklistEntry) String() string {
+	if e.Done {
+		return "[x] " + e.Text
+	}
+	return "[ ] " + e.Text
+}
+
+func (i *Item) SetChecklist(entries []ChecklistEntry) {
+	i.Checklist = entries
+	i.Modified = time.Now()
+}
+
+// ToggleChecklistEntry ticks the entry at the index off or opens it again
+func (i *Item) ToggleChecklistEntry(index int) bool {
+	if index < 0 || index >= len(i.Checklist) {
+		return false
+	}
+
+	/* Copy on write, the previous checklist may still be referenced by the history */
+	i.Checklist = slices.Clone(i.Checklist)
+	i.Checklist[index].Done = !i.Checklist[index].Done
+	i.Modified = time.Now()
+	return true
+}
+
+// ChecklistProgress returns the number of done entries and the number of all entries
+func (i *Item) ChecklistProgress() (int, int) {
+	done := 0
+	for _, entry := range i.Checklist {
+		if entry.Done {
+			done++
+		}
+	}
+	return done, len(i.Checklist)
+}
@@ -0,0 +1,563 @@
+package model
+
+/* This file contains the reversible commands to change a board, which are recorded by the history */
+
+/* ================================================================================ Imports */
+import (
+	"slices"
+	"time"
+)
+
+/* ================================================================================ Public types */
+type AddStageCommand struct {
+	Stage *Stage
+	Index int
+}
+
+type RemoveStageCommand struct {
+	Stage *Stage
+	index int
+}
+
+type RenameStageCommand struct {
+	Stage       *Stage
+	Title       string
+	oldTitle    string
+	oldModified time.Time
+}
+
+type SetWIPLimitCommand struct {
+	Stage       *Stage
+	Limit       int
+	oldLimit    int
+	oldModified time.Time
+}
+
+type MoveStageCommand struct {
+	Stage    *Stage
+	Index    int
+	oldIndex int
+}
+
+type RenameBoardCommand struct {
+	Name    string
+	oldName string
+}
+
+type SetPlainTextCommand struct {
+	PlainText    bool
+	oldPlainText bool
+}
+
+// ClearBoardCommand empties the board to start a new one, which starts a new activity log as well
+type ClearBoardCommand struct {
+	Name          string
+	oldName       string
+	oldPlainText  bool
+	oldViews      []FilterView
+	oldLanes      []*Lane
+	oldStages     []*Stage
+	oldActivities []Activity
+}
+
+type AddLaneCommand struct {
+	Lane  *Lane
+	Index int
+}
+
+// RemoveLaneCommand removes the lane, its items then belong to the first remaining lane
+type RemoveLaneCommand struct {
+	Lane  *Lane
+	index int
+}
+
+type RenameLaneCommand struct {
+	Lane     *Lane
+	Title    string
+	oldTitle string
+}
+
+type MoveLaneCommand struct {
+	Lane     *Lane
+	Index    int
+	oldIndex int
+}
+
+// SaveViewCommand adds the view or replaces the query of the view with the same name
+type SaveViewCommand struct {
+	View     FilterView
+	oldViews []FilterView
+}
+
+type RemoveViewCommand struct {
+	Name     string
+	oldViews []FilterView
+}
+
+// ReplaceBoardCommand replaces the whole board content, e.g. by a merged version, and logs the action, "replaced" by
+// default - the activity log of the board is kept and joined with the one of the new content
+type ReplaceBoardCommand struct {
+	Board  *Board
+	Action ActivityAction
+	old    Board
+}
+
+type AddItemCommand struct {
+	Stage *Stage
+	Item  *Item
+	Index int
+}
+
+type RemoveItemCommand struct {
+	Item  *Item
+	stage *Stage
+	index int
+}
+
+// MoveItemCommand moves the item to the target stage and, unless the lane is nil, to the lane
+type MoveItemCommand struct {
+	Item        *Item
+	Target      *Stage
+	Index       int
+	Lane        *Lane
+	source      *Stage
+	sourceIndex int
+	oldLaneID   string
+	oldMoved    time.Time
+}
+
+type EditItemCommand struct {
+	Item        *Item
+	Title       string
+	Tags        []Tag
+	Description string
+	Style       ItemStyle
+	DataType    string
+	Start       time.Time
+	Due         time.Time
+	Checklist   []ChecklistEntry
+	before      Item
+}
+
+type ToggleChecklistEntryCommand struct {
+	Item        *Item
+	Index       int
+	oldModified time.Time
+}
+
+type SetRecurrenceCommand struct {
+	Item          *Item
+	Recurrence    *Recurrence
+	oldRecurrence *Recurrence
+	oldModified   time.Time
+}
+
+// CreateOccurrencesCommand creates the next occurrences of the done recurring items in their recurrence stages, the
+// recurrences pass on from the items to their occurrences
+type CreateOccurrencesCommand struct {
+	Items       []*Item
+	Today       time.Time
+	Calendars   Calendars
+	occurrences []*Item
+	stages      []*Stage
+	oldModified []time.Time
+}
+
+// SortByDueCommand sorts the items of the stages by their due dates, items without due date go to the end
+type SortByDueCommand struct {
+	Stages   []*Stage
+	oldItems [][]*Item
+}
+
+/* ================================================================================ Public methods */
+func (c *AddStageCommand) Do(b *Board) error {
+	b.InsertStage(c.Index, c.Stage)
+	return nil
+}
+
+func (c *AddStageCommand) Undo(b *Board) error {
+	if !b.RemoveStage(c.Stage) {
+		return ErrStageNotFound
+	}
+	return nil
+}
+
+func (c *RemoveStageCommand) Do(b *Board) error {
+	c.index = b.StageIndex(c.Stage)
+	if !b.RemoveStage(c.Stage) {
+		return ErrStageNotFound
+	}
+	for _, item := range c.Stage.Items {
+		b.logItemActivity(ActivityRemoved, item, c.Stage, nil, nil)
+	}
+	return nil
+}
+
+func (c *RemoveStageCommand) Undo(b *Board) error {
+	b.InsertStage(c.index, c.Stage)
+	for _, item := range c.Stage.Items {
+		b.logItemActivity(ActivityCreated, item, nil, c.Stage, nil)
+	}
+	return nil
+}
+
+func (c *RenameStageCommand) Do(b *Board) error {
+	c.oldTitle = c.Stage.Title
+	c.oldModified = c.Stage.Modified
+	c.Stage.Rename(c.Title)
+	return nil
+}
+
+func (c *RenameStageCommand) Undo(b *Board) error {
+	c.Stage.Title = c.oldTitle
+	c.Stage.Modified = c.oldModified
+	return nil
+}
+
+func (c *SetWIPLimitCommand) Do(b *Board) error {
+	c.oldLimit = c.Stage.WIPLimit
+	c.oldModified = c.Stage.Modified
+	c.Stage.SetWIPLimit(c.Limit)
+	return nil
+}
+
+func (c *SetWIPLimitCommand) Undo(b *Board) error {
+	c.Stage.WIPLimit = c.oldLimit
+	c.Stage.Modified = c.oldModified
+	return nil
+}
+
+func (c *MoveStageCommand) Do(b *Board) error {
+	c.oldIndex = b.StageIndex(c.Stage)
+	return b.MoveStage(c.Stage, c.Index)
+}
+
+func (c *MoveStageCommand) Undo(b *Board) error {
+	return b.MoveStage(c.Stage, c.oldIndex)
+}
+
+func (c *RenameBoardCommand) Do(b *Board) error {
+	c.oldName = b.Name
+	b.Name = c.Name
+	return nil
+}
+
+func (c *RenameBoardCommand) Undo(b *Board) error {
+	b.Name = c.oldName
+	return nil
+}
+
+func (c *ClearBoardCommand) Do(b *Board) error {
+	c.oldName = b.Name
+	c.oldPlainText = b.PlainText
+	c.oldViews = b.Views
+	c.oldLanes = b.Lanes
+	c.oldStages = b.Stages
+	c.oldActivities = b.Activities
+	b.Name = c.Name
+	b.PlainText = false
+	b.Views = nil
+	b.Lanes = nil
+	b.Stages = nil
+	b.Activities = nil
+	return nil
+}
+
+func (c *ClearBoardCommand) Undo(b *Board) error {
+	b.Name = c.oldName
+	b.PlainText = c.oldPlainText
+	b.Views = c.oldViews
+	b.Lanes = c.oldLanes
+	b.Stages = c.oldStages
+	b.Activities = c.oldActivities
+	b.LogActivity(Activity{Action: ActivityRestored})
+	return nil
+}
+
+func (c *SetPlainTextCommand) Do(b *Board) error {
+	c.oldPlainText = b.PlainText
+	b.PlainText = c.PlainText
+	return nil
+}
+
+func (c *SetPlainTextCommand) Undo(b *Board) error {
+	b.PlainText = c.oldPlainText
+	return nil
+}
+
+func (c *AddLaneCommand) Do(b *Board) error {
+	b.InsertLane(c.Index, c.Lane)
+	return nil
+}
+
+func (c *AddLaneCommand) Undo(b *Board) error {
+	if !b.RemoveLane(c.Lane) {
+		return ErrLaneNotFound
+	}
+	return nil
+}
+
+func (c *RemoveLaneCommand) Do(b *Board) error {
+	c.index = b.LaneIndex(c.Lane)
+	if !b.RemoveLane(c.Lane) {
+		return ErrLaneNotFound
+	}
+	return nil
+}
+
+func (c *RemoveLaneCommand) Undo(b *Board) error {
+	b.InsertLane(c.index, c.Lane)
+	return nil
+}
+
+func (c *RenameLaneCommand) Do(b *Board) error {
+	c.oldTitle = c.Lane.Title
+	c.Lane.Title = c.Title
+	return nil
+}
+
+func (c *RenameLaneCommand) Undo(b *Board) error {
+	c.Lane.Title = c.oldTitle
+	return nil
+}
+
+func (c *MoveLaneCommand) Do(b *Board) error {
+	c.oldIndex = b.LaneIndex(c.Lane)
+	return b.MoveLane(c.Lane, c.Index)
+}
+
+func (c *MoveLaneCommand) Undo(b *Board) error {
+	return b.MoveLane(c.Lane, c.oldIndex)
+}
+
+func (c *SaveViewCommand) Do(b *Board) error {
+	c.oldViews = slices.Clone(b.Views)
+	b.SetView(c.View)
+	return nil
+}
+
+func (c *SaveViewCommand) Undo(b *Board) error {
+	b.Views = c.oldViews
+	return nil
+}
+
+func (c *RemoveViewCommand) Do(b *Board) error {
+	c.oldViews = slices.Clone(b.Views)
+	if !b.RemoveView(c.Name) {
+		return ErrViewNotFound
+	}
+	return nil
+}
+
+func (c *RemoveViewCommand) Undo(b *Board) error {
+	b.Views = c.oldViews
+	return nil
+}
+
+func (c *ReplaceBoardCommand) Do(b *Board) error {
+	action := c.Action
+	if action == "" {
+		action = ActivityReplaced
+	}
+
+	c.old = *b
+	*b = *c.Board
+	b.Activities = mergeActivities(c.old.Activities, c.Board.Activities)
+	b.LogActivity(Activity{Action: action})
+	return nil
+}
+
+func (c *ReplaceBoardCommand) Undo(b *Board) error {
+	activities := b.Activities
+	*b = c.old
+	b.Activities = activities
+	b.LogActivity(Activity{Action: ActivityRestored})
+	return nil
+}
+
+func (c *AddItemCommand) Do(b *Board) error {
+	if b.StageIndex(c.Stage) < 0 {
+		return ErrStageNotFound
+	}
+	c.Stage.InsertItem(c.Index, c.Item)
+	b.logItemActivity(ActivityCreated, c.Item, nil, c.Stage, nil)
+	return nil
+}
+
+func (c *AddItemCommand) Undo(b *Board) error {
+	if !c.Stage.RemoveItem(c.Item) {
+		return ErrItemNotFound
+	}
+	b.logItemActivity(ActivityRemoved, c.Item, c.Stage, nil, nil)
+	return nil
+}
+
+func (c *RemoveItemCommand) Do(b *Board) error {
+	c.stage = b.ItemStage(c.Item)
+	if c.stage == nil {
+		return ErrItemNotFound
+	}
+	c.index = c.stage.ItemIndex(c.Item)
+	c.stage.RemoveItem(c.Item)
+	b.logItemActivity(ActivityRemoved, c.Item, c.stage, nil, nil)
+	return nil
+}
+
+func (c *RemoveItemCommand) Undo(b *Board) error {
+	if b.StageIndex(c.stage) < 0 {
+		return ErrStageNotFound
+	}
+	c.stage.InsertItem(c.index, c.Item)
+	b.logItemActivity(ActivityCreated, c.Item, nil, c.stage, nil)
+	return nil
+}
+
+func (c *MoveItemCommand) Do(b *Board) error {
+	c.source = b.ItemStage(c.Item)
+	if c.source == nil {
+		return ErrItemNotFound
+	}
+	if c.Lane != nil && b.LaneIndex(c.Lane) < 0 {
+		return ErrLaneNotFound
+	}
+	c.sourceIndex = c.source.ItemIndex(c.Item)
+	c.oldLaneID = c.Item.LaneID
+	c.oldMoved = c.Item.Moved
+	if err := b.MoveItem(c.Item, c.Target, c.Index); err != nil {
+		return err
+	}
+
+	if c.Lane != nil {
+		c.Item.LaneID = c.Lane.ID
+	}
+
+	/* Reordering within a stage is no activity */
+	if c.source != c.Target {
+		b.logItemActivity(ActivityMoved, c.Item, c.source, c.Target, nil)
+	}
+	return nil
+}
+
+func (c *MoveItemCommand) Undo(b *Board) error {
+	if err := b.MoveItem(c.Item, c.source, c.sourceIndex); err != nil {
+		return err
+	}
+	c.Item.LaneID = c.oldLaneID
+	c.Item.Moved = c.oldMoved
+
+	if c.source != c.Target {
+		b.logItemActivity(ActivityMoved, c.Item, c.Target, c.source, nil)
+	}
+	return nil
+}
+
+func (c *EditItemCommand) Do(b *Board) error {
+	c.before = *c.Item
+	c.Item.Update(c.Title, c.Tags, c.Description, c.Style, c.DataType)
+	c.Item.SetDates(c.Start, c.Due)
+	c.Item.SetChecklist(c.Checklist)
+
+	if changes := ItemChanges(&c.before, c.Item); len(changes) > 0 {
+		b.logItemActivity(ActivityEdited, c.Item, nil, nil, changes)
+	}
+	return nil
+}
+
+func (c *EditItemCommand) Undo(b *Board) error {
+	after := *c.Item
+	c.Item.Update(c.before.Title, c.before.Tags, c.before.Description, c.before.Style, c.before.DataType)
+	c.Item.SetDates(c.before.Start, c.before.Due)
+	c.Item.SetChecklist(c.before.Checklist)
+	c.Item.Modified = c.before.Modified
+
+	if changes := ItemChanges(&after, c.Item); len(changes) > 0 {
+		b.logItemActivity(ActivityEdited, c.Item, nil, nil, changes)
+	}
+	return nil
+}
+
+func (c *ToggleChecklistEntryCommand) Do(b *Board) error {
+	c.oldModified = c.Item.Modified
+	if !c.Item.ToggleChecklistEntry(c.Index) {
+		return ErrChecklistEntryNotFound
+	}
+	return nil
+}
+
+func (c *ToggleChecklistEntryCommand) Undo(b *Board) error {
+	c.Item.ToggleChecklistEntry(c.Index)
+	c.Item.Modified = c.oldModified
+	return nil
+}
+
+func (c *SortByDueCommand) Do(b *Board) error {
+	c.oldItems = make([][]*Item, len(c.Stages))
+	for i, stage := range c.Stages {
+		c.oldItems[i] = slices.Clone(stage.Items)
+		SortItemsByDue(stage.Items)
+	}
+	return nil
+}
+
+func (c *SortByDueCommand) Undo(b *Board) error {
+	for i, stage := range c.Stages {
+		stage.Items = c.oldItems[i]
+	}
+	return nil
+}
+
+func (c *SetRecurrenceCommand) Do(b *Board) error {
+	c.oldRecurrence = c.Item.Recurrence
+	c.oldModified = c.Item.Modified
+	c.Item.SetRecurrence(c.Recurrence)
+	return nil
+}
+
+func (c *SetRecurrenceCommand) Undo(b *Board) error {
+	c.Item.Recurrence = c.oldRecurrence
+	c.Item.Modified = c.oldModified
+	return nil
+}
+
+func (c *CreateOccurrencesCommand) Do(b *Board) error {
+	/* The occurrences are only created once, so a redo restores the same items */
+	if c.occurrences == nil {
+		c.occurrences = make([]*Item, len(c.Items))
+		c.stages = make([]*Stage, len(c.Items))
+		for i, item := range c.Items {
+			c.occurrences[i] = item.Occurrence(item.NextOccurrence(c.Today, c.Calendars))
+			c.stages[i] = b.RecurrenceStage(item)
+		}
+	}
+
+	/* All stages are checked first, so a failing command changes nothing */
+	for _, stage := range c.stages {
+		if stage == nil || b.StageIndex(stage) < 0 {
+			return ErrStageNotFound
+		}
+	}
+
+	c.oldModified = make([]time.Time, len(c.Items))
+	for i, item := range c.Items {
+		c.stages[i].AppendItem(c.occurrences[i])
+		c.oldModified[i] = item.Modified
+		item.SetRecurrence(nil)
+		b.logItemActivity(ActivityCreated, c.occurrences[i], nil, c.stages[i], nil)
+	}
+	return nil
+}
+
+// Occurrences returns the items created by Do
+func (c *CreateOccurrencesCommand) Occurrences() []*Item {
+	return c.occurrences
+}
+
+func (c *CreateOccurrencesCommand) Undo(b *Board) error {
+	for i, item := range c.Items {
+		c.stages[i].RemoveItem(c.occurrences[i])
+		item.Recurrence = c.occurrences[i].Recurrence
+		item.Modified = c.oldModified[i]
+		b.logItemActivity(ActivityRemoved, c.occurrences[i], c.stages[i], nil, nil)
+	}
+	return nil
+}
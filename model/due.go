@@ -0,0 +1,106 @@
+package model
+
+/* This file contains the optional start and due dates of items and the due state derived from them, both dates are
+   calendar days without time of day and unset if zero */
+
+/* ================================================================================ Imports */
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+/* ================================================================================ Constants */
+const (
+	DATE_FORMAT   = "2006-01-02"
+	DUE_SOON_DAYS = 2
+)
+
+/* ================================================================================ Public types */
+type DueState int
+
+const (
+	DueNone DueState = iota
+	DueLater
+	DueSoon
+	DueOverdue
+)
+
+/* ================================================================================ Public functions */
+// Date truncates the time to the start of its calendar day, the zero time stays zero
+func Date(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// ParseDate parses a date in the DATE_FORMAT as local calendar day, the empty string is the zero time
+func ParseDate(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(DATE_FORMAT, text, time.Local)
+}
+
+// FormatDate formats the date in the DATE_FORMAT, the zero time as the empty string
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(DATE_FORMAT)
+}
+
+// SortItemsByDue sorts the items by due date, items without due date keep their order behind the others
+func SortItemsByDue(items []*Item) {
+	slices.SortStableFunc(items, func(a, b *Item) int {
+		if a.Due.IsZero() || b.Due.IsZero() {
+			return cmp.Compare(dueSortKey(a), dueSortKey(b))
+		}
+		return a.Due.Compare(b.Due)
+	})
+}
+
+/* ================================================================================ Public methods */
+func (i *Item) SetDates(start, due time.Time) {
+	i.Start = Date(start)
+	i.Due = Date(due)
+	i.Modified = time.Now()
+}
+
+// DueState returns whether the item is overdue or due within the next DUE_SOON_DAYS days at the given time
+func (i *Item) DueState(now time.Time) DueState {
+	if i.Due.IsZero() {
+		return DueNone
+	}
+
+	today := Date(now)
+	switch {
+	case i.Due.Before(today):
+		return DueOverdue
+	case i.Due.Before(today.AddDate(0, 0, DUE_SOON_DAYS+1)):
+		return DueSoon
+	default:
+		return DueLater
+	}
+}
+
+// DateTags returns the set dates as "start=..." and "due=..." tag expressions, so filters can compare them like tags
+func (i *Item) DateTags() []Tag {
+	tags := []Tag{}
+	if !i.Start.IsZero() {
+		tags = append(tags, Tag{"start=" + FormatDate(i.Start)})
+	}
+	if !i.Due.IsZero() {
+		tags = append(tags, Tag{"due=" + FormatDate(i.Due)})
+	}
+	return tags
+}
+
+/* ================================================================================ Private functions */
+func dueSortKey(item *Item) int {
+	if item.Due.IsZero() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,392 @@
+package model
+
+/* This file contains the export of a board to other formats than the JSON of the app: a Markdown document, a flat CSV
+   table with one row per item and a self-contained HTML page looking like the board */
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"image/color"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+)
+
+/* ================================================================================ Constants */
+const (
+	HTML_TEMPLATE = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { margin: 0; padding: 16px; font-family: sans-serif; background: #303030; color: #ffffff; }
+h1 { margin: 0 0 16px 0; font-size: 1.5em; }
+.board { display: flex; align-items: flex-start; gap: 8px; overflow-x: auto; }
+.stage { flex: 0 0 280px; border-right: 1px solid #606060; padding-right: 8px; }
+.stage > h2 { margin: 0 0 8px 0; font-size: 1.1em; }
+.stage > h2 span { font-weight: normal; opacity: 0.7; }
+.lane > h3 { margin: 8px 0 4px 0; padding: 2px 4px; font-size: 0.95em; background: rgba(255, 255, 255, 0.1); }
+.item { margin-bottom: 8px; padding: 6px 8px; }
+.item h4 { margin: 0 0 4px 0; font-size: 1em; }
+.tag, .dates { display: inline-block; margin: 0 4px 4px 0; padding: 0 4px; font-size: 0.8em; font-style: italic; }
+.dates { background: #606060; color: #ffffff; font-style: normal; font-weight: bold; }
+.checklist { margin: 4px 0; padding-left: 0; list-style: none; font-family: monospace; }
+.description { font-size: 0.9em; overflow-wrap: break-word; }
+.description pre { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<div class="board">
+{{- range .Stages}}
+<div class="stage">
+<h2>{{.Title}} <span>{{.WIPLimit}}</span></h2>
+{{- range .Lanes}}
+<div class="lane">
+{{- if .Title}}
+<h3>{{.Title}}</h3>
+{{- end}}
+{{- range .Items}}
+<div class="item" style="{{.Style}}">
+<h4>{{.Title}}</h4>
+{{- if .Dates}}<span class="dates">{{.Dates}}</span>{{end}}
+{{- $tagStyle := .TagStyle}}
+{{- range .Tags}}<span class="tag" style="{{$tagStyle}}">{{.}}</span>{{end}}
+{{- if .Checklist}}
+<ul class="checklist">
+{{- range .Checklist}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<div class="description">{{.Description}}</div>
+</div>
+{{- end}}
+</div>
+{{- end}}
+</div>
+{{- end}}
+</div>
+</body>
+</html>
+`
+)
+
+/* ================================================================================ Public variables */
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+/* ================================================================================ Public types */
+type ExportFormat string
+
+const (
+	ExportJSON     ExportFormat = "json"
+	ExportMarkdown ExportFormat = "markdown"
+	ExportCSV      ExportFormat = "csv"
+	ExportHTML     ExportFormat = "html"
+)
+
+/* ================================================================================ Private types */
+type htmlStage struct {
+	Title    string
+	WIPLimit string
+	Lanes    []htmlLane
+}
+
+type htmlLane struct {
+	Title string
+	Items []htmlItem
+}
+
+type htmlItem struct {
+	Title       string
+	Style       template.CSS
+	TagStyle    template.CSS
+	Tags        []string
+	Dates       string
+	Checklist   []ChecklistEntry
+	Description template.HTML
+}
+
+/* ================================================================================ Private variables */
+var htmlTemplate = template.Must(template.New("board").Parse(HTML_TEMPLATE))
+
+/* ================================================================================ Public functions */
+// ExportFormats returns all formats, the JSON of the app first
+func ExportFormats() []ExportFormat {
+	return []ExportFormat{ExportJSON, ExportMarkdown, ExportCSV, ExportHTML}
+}
+
+// ParseExportFormat returns the format of the name or file extension (e.g. "md", ".csv")
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "json":
+		return ExportJSON, nil
+	case "markdown", "md":
+		return ExportMarkdown, nil
+	case "csv":
+		return ExportCSV, nil
+	case "html", "htm":
+		return ExportHTML, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownExportFormat, name)
+	}
+}
+
+// ExportFormatOfPath returns the format matching the extension of the file path, JSON for unknown extensions
+func ExportFormatOfPath(path string) ExportFormat {
+	if format, err := ParseExportFormat(filepath.Ext(path)); err == nil {
+		return format
+	}
+	return ExportJSON
+}
+
+/* ================================================================================ Public methods */
+func (f ExportFormat) Extension() string {
+	if f == ExportMarkdown {
+		return ".md"
+	}
+	return "." + string(f)
+}
+
+// Export returns the board in the format
+func (b *Board) Export(format ExportFormat) ([]byte, error) {
+	switch format {
+	case ExportJSON:
+		return b.Data()
+	case ExportMarkdown:
+		return b.MarkdownData(), nil
+	case ExportCSV:
+		return b.CSVData()
+	case ExportHTML:
+		return b.HTMLData()
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExportFormat, format)
+	}
+}
+
+// MarkdownData returns the board as Markdown document, stages are headings (and lanes subheadings) and items are
+// bullets with their tags, dates, checklist and description
+func (b *Board) MarkdownData() []byte {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "# %s\n", b.exportName())
+
+	for _, stage := range b.Stages {
+		fmt.Fprintf(buffer, "\n## %s%s\n", stage.Title, wipLimitText(stage, " "))
+
+		for _, lane := range b.exportLanes() {
+			items := b.exportItems(stage, lane)
+			if lane != nil {
+				fmt.Fprintf(buffer, "\n### %s\n", lane.Title)
+			}
+			if len(items) > 0 {
+				buffer.WriteString("\n")
+			}
+
+			for _, item := range items {
+				fmt.Fprintf(buffer, "- **%s**", item.Title)
+				for _, tag := range item.Tags {
+					fmt.Fprintf(buffer, " `%s`", tag.Expression)
+				}
+				if dates := exportDates(item); dates != "" {
+					fmt.Fprintf(buffer, " (%s)", dates)
+				}
+				buffer.WriteString("\n")
+
+				for _, entry := range item.Checklist {
+					fmt.Fprintf(buffer, "  - %s\n", entry)
+				}
+				if description := strings.TrimSpace(item.Description); description != "" {
+					buffer.WriteString("\n")
+					for _, line := range strings.Split(description, "\n") {
+						fmt.Fprintf(buffer, "  %s\n", line)
+					}
+					buffer.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+// CSVData returns the items as CSV table with one row per item, each tag key ("key=value" tags) gets its own column,
+// simple tags are listed in the Tags column
+func (b *Board) CSVData() ([]byte, error) {
+	keys := []string{}
+	for _, stage := range b.Stages {
+		for _, item := range stage.Items {
+			for _, tag := range item.Tags {
+				if key, _, found := strings.Cut(tag.Expression, "="); found && !slices.Contains(keys, key) {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+
+	header := []string{"ID", "Stage", "Lane", "Title", "Description", "Tags"}
+	header = append(header, keys...)
+	header = append(header, "Foreground", "Background", "Created", "Modified", "Start", "Due", "Recurrence", "Checklist")
+
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, stage := range b.Stages {
+		for _, item := range stage.Items {
+			laneTitle := ""
+			if lane := b.ItemLane(item); lane != nil {
+				laneTitle = lane.Title
+			}
+
+			simpleTags := []string{}
+			values := make([]string, len(keys))
+			for _, tag := range item.Tags {
+				key, value, found := strings.Cut(tag.Expression, "=")
+				if !found {
+					simpleTags = append(simpleTags, tag.Expression)
+					continue
+				}
+				i := slices.Index(keys, key)
+				if values[i] != "" {
+					value = values[i] + "; " + value
+				}
+				values[i] = value
+			}
+
+			checklist := ""
+			if done, total := item.ChecklistProgress(); total > 0 {
+				checklist = fmt.Sprintf("%d/%d", done, total)
+			}
+
+			record := []string{item.ID, stage.Title, laneTitle, item.Title, item.Description, strings.Join(simpleTags, "; ")}
+			record = append(record, values...)
+			record = append(record, hexColor(item.Style.Foreground), hexColor(item.Style.Background), item.Created.Format(time.RFC3339), item.Modified.Format(time.RFC3339),
+				FormatDate(item.Start), FormatDate(item.Due), item.Recurrence.String(), checklist)
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// HTMLData returns the board as self-contained HTML page with the stages side by side and the items in their colors,
+// descriptions are rendered from Markdown unless the board keeps plain text
+func (b *Board) HTMLData() ([]byte, error) {
+	stages := []htmlStage{}
+
+	for _, stage := range b.Stages {
+		exported := htmlStage{Title: stage.Title, WIPLimit: wipLimitText(stage, "")}
+
+		for _, lane := range b.exportLanes() {
+			exportedLane := htmlLane{}
+			if lane != nil {
+				exportedLane.Title = lane.Title
+			}
+
+			for _, item := range b.exportItems(stage, lane) {
+				description, err := b.htmlDescription(item.Description)
+				if err != nil {
+					return nil, err
+				}
+
+				tags := make([]string, len(item.Tags))
+				for i, tag := range item.Tags {
+					tags[i] = tag.DisplayString()
+				}
+
+				exportedLane.Items = append(exportedLane.Items, htmlItem{
+					Title:       item.Title,
+					Style:       template.CSS(fmt.Sprintf("color: %s; background: %s;", hexColor(item.Style.Foreground), hexColor(item.Style.Background))),
+					TagStyle:    template.CSS(fmt.Sprintf("color: %s; background: %s;", hexColor(item.Style.Background), hexColor(item.Style.Foreground))),
+					Tags:        tags,
+					Dates:       exportDates(item),
+					Checklist:   item.Checklist,
+					Description: description,
+				})
+			}
+			exported.Lanes = append(exported.Lanes, exportedLane)
+		}
+		stages = append(stages, exported)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := htmlTemplate.Execute(buffer, map[string]any{"Name": b.exportName(), "Stages": stages}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+/* ================================================================================ Private methods */
+func (b *Board) exportName() string {
+	if b.Name == "" {
+		return "Board"
+	}
+	return b.Name
+}
+
+// exportLanes returns the lanes of the board, or a single nil lane standing for all items if it has none
+func (b *Board) exportLanes() []*Lane {
+	if len(b.Lanes) < 1 {
+		return []*Lane{nil}
+	}
+	return b.Lanes
+}
+
+func (b *Board) exportItems(stage *Stage, lane *Lane) []*Item {
+	if lane == nil {
+		return stage.Items
+	}
+	return b.LaneItems(stage, lane)
+}
+
+func (b *Board) htmlDescription(description string) (template.HTML, error) {
+	if b.PlainText {
+		return template.HTML("<pre>" + template.HTMLEscapeString(description) + "</pre>"), nil
+	}
+
+	/* goldmark escapes raw HTML unless explicitly enabled, so the result is safe to embed */
+	buffer := &bytes.Buffer{}
+	if err := goldmark.Convert([]byte(description), buffer); err != nil {
+		return "", err
+	}
+	return template.HTML(buffer.String()), nil
+}
+
+/* ================================================================================ Private functions */
+func wipLimitText(stage *Stage, prefix string) string {
+	if stage.WIPLimit < 1 {
+		return ""
+	}
+	return fmt.Sprintf("%s(%d/%d)", prefix, len(stage.Items), stage.WIPLimit)
+}
+
+func exportDates(item *Item) string {
+	dates := []string{}
+	if !item.Start.IsZero() {
+		dates = append(dates, "start "+FormatDate(item.Start))
+	}
+	if !item.Due.IsZero() {
+		dates = append(dates, "due "+FormatDate(item.Due))
+	}
+	if item.Recurrence != nil {
+		dates = append(dates, "recurs "+item.Recurrence.String())
+	}
+	return strings.Join(dates, ", ")
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
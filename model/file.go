@@ -0,0 +1,178 @@
+package model
+
+/* This file contains crash-safe saving of boards to the local file system, including rotating backup generations */
+
+/* ================================================================================ Imports */
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* ================================================================================ Constants */
+const (
+	BACKUP_SUFFIX      = ".bak"
+	BACKUP_TIME_FORMAT = "20060102-150405"
+	BACKUP_INTERVAL    = 10 * time.Minute
+	NEW_FILE_MODE      = 0644
+)
+
+/* ================================================================================ Public functions */
+// WriteFileAtomic writes the data to a synced temporary file next to the target and renames it into place, so
+// the target always holds either the old or the new content, even if the application crashes or the disk is full
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	/* Keep the permissions of an existing file, the temporary file is only accessible by the owner */
+	if info, err := os.Stat(path); err == nil {
+		os.Chmod(tempPath, info.Mode().Perm())
+	} else {
+		os.Chmod(tempPath, NEW_FILE_MODE)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	/* Sync the directory to persist the rename, which is not supported on every platform, so errors are ignored */
+	if dirFile, err := os.Open(dir); err == nil {
+		dirFile.Sync()
+		dirFile.Close()
+	}
+
+	return nil
+}
+
+// BackupPaths returns the paths of all backups of the given file, newest first
+func BackupPaths(path string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(path) + "."
+	paths := []string{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, BACKUP_SUFFIX) {
+			continue
+		}
+		if _, err := time.ParseInLocation(BACKUP_TIME_FORMAT, strings.TrimSuffix(strings.TrimPrefix(name, prefix), BACKUP_SUFFIX), time.Local); err != nil {
+			continue
+		}
+		paths = append(paths, filepath.Join(filepath.Dir(path), name))
+	}
+
+	/* The time format sorts chronologically */
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+
+	return paths, nil
+}
+
+// BackupTime returns the time a backup was created at, parsed from its file name
+func BackupTime(backupPath string) (time.Time, error) {
+	name := strings.TrimSuffix(filepath.Base(backupPath), BACKUP_SUFFIX)
+	stamp := name[strings.LastIndex(name, ".")+1:]
+
+	return time.ParseInLocation(BACKUP_TIME_FORMAT, stamp, time.Local)
+}
+
+// BackupFile copies the current content of the file to a timestamped backup next to it and deletes all but the
+// newest count backups, a new backup is only created if the newest one is older than the backup interval
+func BackupFile(path string, count int) error {
+	if count < 1 {
+		return nil
+	}
+
+	backups, err := BackupPaths(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due := true
+	if len(backups) > 0 {
+		if newest, err := BackupTime(backups[0]); err == nil && now.Sub(newest) < BACKUP_INTERVAL {
+			due = false
+		}
+	}
+
+	if due {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		if len(data) > 0 {
+			backupPath := path + "." + now.Format(BACKUP_TIME_FORMAT) + BACKUP_SUFFIX
+			if err := WriteFileAtomic(backupPath, data); err != nil {
+				return err
+			}
+			if info, err := os.Stat(path); err == nil {
+				os.Chmod(backupPath, info.Mode().Perm())
+			}
+			backups = append([]string{backupPath}, backups...)
+		}
+	}
+
+	for _, backup := range backups[min(count, len(backups)):] {
+		if err := os.Remove(backup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveFile backs up the existing file and atomically replaces it by the board data
+func SaveFile(b *Board, path string, backupCount int) error {
+	data, err := b.Data()
+	if err != nil {
+		return err
+	}
+
+	if err := BackupFile(path, backupCount); err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(path, data)
+}
+
+func LoadFile(path string) (*Board, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Board{}
+	if err := b.Load(data); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
@@ -0,0 +1,606 @@
+package model
+
+/* This file contains the tag filter query language, which combines conditions on the tags of items with boolean
+   operators, e.g.:
+
+     project=alpha AND NOT status=blocked    key=value conditions, negation
+     (prio>=2 OR urgent) AND due<2025-01-01  numeric and date comparisons, key-only conditions, grouping
+     project=al* || owner=?ob                wildcards, symbolic operators (&&, ||, !)
+     urgent; later                           ";" separated lists as OR, like the tag edit strings
+
+   The keywords AND, OR and NOT are upper case. Conditions may contain spaces, keywords and special characters can
+   be quoted ("a AND b"). A condition without comparison matches tags with this key or this exact expression. The
+   start and due dates of items are matched like the tags "start=..." and "due=...", e.g. due<today for overdue items. */
+
+/* ================================================================================ Imports */
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/* ================================================================================ Public types */
+// Filter is a parsed filter query, the empty filter matches every item
+type Filter struct {
+	Query string
+	root  filterNode
+}
+
+type FilterSyntaxError struct {
+	Position int
+	Message  string
+}
+
+/* ================================================================================ Private types */
+type filterNode interface {
+	matches(item *Item) bool
+}
+
+type filterAnd struct {
+	left, right filterNode
+}
+
+type filterOr struct {
+	left, right filterNode
+}
+
+type filterNot struct {
+	operand filterNode
+}
+
+// filterCondition compares the tags of an item, key and value are patterns with wildcards, the raw value is used
+// for numeric and date comparisons
+type filterCondition struct {
+	key      *regexp.Regexp
+	operator string
+	value    *regexp.Regexp
+	rawValue string
+	wildcard bool
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenWord filterTokenKind = iota
+	filterTokenQuoted
+	filterTokenAnd
+	filterTokenOr
+	filterTokenNot
+	filterTokenLeft
+	filterTokenRight
+	filterTokenEnd
+)
+
+type filterToken struct {
+	kind     filterTokenKind
+	text     string
+	position int
+	end      int
+}
+
+// filterRune is a character of a condition, quoted characters have no special meaning
+type filterRune struct {
+	r      rune
+	quoted bool
+}
+
+type filterParser struct {
+	tokens []filterToken
+	index  int
+}
+
+/* ================================================================================ Private variables */
+// filterDateFormats are the accepted formats of date values, the Gregorian date items use the first and the last
+var filterDateFormats = []string{"2006-01-02", "2006/01/02", "2006.01.02", "2006年01月02日"}
+
+/* ================================================================================ Public functions */
+// ParseFilter parses a filter query, syntax errors are returned as *FilterSyntaxError
+func ParseFilter(query string) (*Filter, error) {
+	tokens, err := tokenizeFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &filterParser{tokens: tokens}
+	parser.skipSeparators()
+	if parser.peek().kind == filterTokenEnd {
+		return &Filter{Query: query}, nil
+	}
+
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token.kind != filterTokenEnd {
+		return nil, &FilterSyntaxError{token.position, fmt.Sprintf("unexpected %q", token.text)}
+	}
+
+	return &Filter{Query: query, root: root}, nil
+}
+
+// FilterTerm returns the filter condition matching exactly the given tag, quoted if necessary
+func FilterTerm(tag Tag) string {
+	expression := tag.Expression
+
+	needsQuotes := strings.TrimSpace(expression) != expression || strings.ContainsAny(expression, `()";&|!<>*?\`)
+	for _, word := range strings.Fields(expression) {
+		if word == "AND" || word == "OR" || word == "NOT" {
+			needsQuotes = true
+		}
+	}
+
+	if !needsQuotes {
+		return expression
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(expression) + `"`
+}
+
+// ToggleFilterTag adds the tag as ";" separated alternative to the query, or removes it if it is already one
+func ToggleFilterTag(query string, tag Tag) string {
+	term := FilterTerm(tag)
+	parts := []string{}
+	found := false
+
+	for _, part := range splitFilterAlternatives(query) {
+		if part == term {
+			found = true
+		} else {
+			parts = append(parts, part)
+		}
+	}
+	if !found {
+		parts = append(parts, term)
+	}
+
+	if len(parts) < 1 {
+		return ""
+	}
+	return strings.Join(parts, "; ") + "; "
+}
+
+/* ================================================================================ Public methods */
+// Matches reports whether the item satisfies the filter
+func (f *Filter) Matches(item *Item) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.matches(item)
+}
+
+func (f *Filter) IsEmpty() bool {
+	return f == nil || f.root == nil
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("%s (at character %d)", e.Message, e.Position+1)
+}
+
+/* ================================================================================ Private methods */
+func (n *filterAnd) matches(item *Item) bool {
+	return n.left.matches(item) && n.right.matches(item)
+}
+
+func (n *filterOr) matches(item *Item) bool {
+	return n.left.matches(item) || n.right.matches(item)
+}
+
+func (n *filterNot) matches(item *Item) bool {
+	return !n.operand.matches(item)
+}
+
+func (n *filterCondition) matches(item *Item) bool {
+	for _, tag := range slices.Concat(item.Tags, item.DateTags()) {
+		key, value, _ := strings.Cut(tag.Expression, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch n.operator {
+		case "":
+			if n.key.MatchString(key) || n.key.MatchString(tag.Expression) {
+				return true
+			}
+		case "=":
+			if n.key.MatchString(key) && n.valueEqual(value) {
+				return true
+			}
+		case "!=":
+			if n.key.MatchString(key) && !n.valueEqual(value) {
+				return true
+			}
+		default:
+			if n.key.MatchString(key) && n.valueCompare(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (n *filterCondition) valueEqual(value string) bool {
+	if n.wildcard {
+		return n.value.MatchString(value)
+	}
+	if a, b, ok := parseFilterNumbers(value, n.rawValue); ok {
+		return a == b
+	}
+	if a, b, ok := parseFilterDates(value, n.rawValue); ok {
+		return a.Equal(b)
+	}
+	return value == n.rawValue
+}
+
+// valueCompare compares numbers or dates, other values never satisfy an order comparison
+func (n *filterCondition) valueCompare(value string) bool {
+	comparison := 0
+	if a, b, ok := parseFilterNumbers(value, n.rawValue); ok {
+		comparison = cmp.Compare(a, b)
+	} else if a, b, ok := parseFilterDates(value, n.rawValue); ok {
+		comparison = a.Compare(b)
+	} else {
+		return false
+	}
+
+	switch n.operator {
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	default:
+		return comparison >= 0
+	}
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.index]
+}
+
+func (p *filterParser) next() filterToken {
+	token := p.tokens[p.index]
+	if token.kind != filterTokenEnd {
+		p.index++
+	}
+	return token
+}
+
+func (p *filterParser) skipSeparators() {
+	for token := p.peek(); token.kind == filterTokenOr && token.text == ";"; token = p.peek() {
+		p.next()
+	}
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == filterTokenOr {
+		operator := p.next()
+
+		/* Empty alternatives of ";" separated lists are ignored, e.g. the trailing one of "a; b; " */
+		if operator.text == ";" {
+			p.skipSeparators()
+			if kind := p.peek().kind; kind == filterTokenEnd || kind == filterTokenRight {
+				break
+			}
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case filterTokenAnd:
+			p.next()
+		case filterTokenNot, filterTokenLeft, filterTokenWord, filterTokenQuoted:
+			/* Juxtaposed operands are combined with AND, e.g. "(a OR b) NOT c" */
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterAnd{left, right}
+	}
+}
+
+func (p *filterParser) parseNot() (filterNode, error) {
+	token := p.peek()
+
+	switch token.kind {
+	case filterTokenNot:
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNot{operand}, nil
+
+	case filterTokenLeft:
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != filterTokenRight {
+			return nil, &FilterSyntaxError{token.position, "missing closing parenthesis"}
+		}
+		p.next()
+		return node, nil
+
+	case filterTokenWord, filterTokenQuoted:
+		return p.parseCondition()
+
+	case filterTokenEnd:
+		if p.index > 0 {
+			previous := p.tokens[p.index-1]
+			return nil, &FilterSyntaxError{previous.position, fmt.Sprintf("expected a tag after %q", previous.text)}
+		}
+		return nil, &FilterSyntaxError{token.position, "expected a tag"}
+
+	default:
+		return nil, &FilterSyntaxError{token.position, fmt.Sprintf("expected a tag before %q", token.text)}
+	}
+}
+
+// parseCondition joins the following words and quoted strings to a condition, keeping the spacing between them
+func (p *filterParser) parseCondition() (filterNode, error) {
+	position := p.peek().position
+	text := []filterRune{}
+	end := -1
+
+	for token := p.peek(); token.kind == filterTokenWord || token.kind == filterTokenQuoted; token = p.peek() {
+		p.next()
+
+		if end >= 0 && token.position > end {
+			text = append(text, filterRune{' ', false})
+		}
+		for _, r := range token.text {
+			text = append(text, filterRune{r, token.kind == filterTokenQuoted})
+		}
+		end = token.end
+	}
+
+	condition := &filterCondition{}
+	key := text
+	value := []filterRune{}
+
+	for i := 0; i < len(text); i++ {
+		if text[i].quoted || !strings.ContainsRune("=<>!", text[i].r) {
+			continue
+		}
+
+		operator := string(text[i].r)
+		if i+1 < len(text) && !text[i+1].quoted && text[i+1].r == '=' && operator != "=" {
+			operator += "="
+		}
+		if operator == "!" {
+			continue
+		}
+
+		condition.operator = operator
+		key, value = text[:i], text[i+len(operator):]
+		break
+	}
+
+	key, value = trimFilterRunes(key), trimFilterRunes(value)
+	if len(key) < 1 {
+		return nil, &FilterSyntaxError{position, fmt.Sprintf("missing tag name before %q", condition.operator)}
+	}
+	if len(value) < 1 && condition.operator != "" && condition.operator != "=" && condition.operator != "!=" {
+		return nil, &FilterSyntaxError{position, fmt.Sprintf("missing value after %q", condition.operator)}
+	}
+
+	condition.key, _ = filterPattern(key)
+	condition.value, condition.wildcard = filterPattern(value)
+	for _, r := range value {
+		condition.rawValue += string(r.r)
+	}
+	if strings.EqualFold(condition.rawValue, "today") {
+		condition.rawValue = time.Now().Format(filterDateFormats[0])
+	}
+
+	return condition, nil
+}
+
+/* ================================================================================ Private functions */
+func tokenizeFilter(query string) ([]filterToken, error) {
+	runes := []rune(query)
+	tokens := []filterToken{}
+
+	isDelimiter := func(i int) bool {
+		r := runes[i]
+		return unicode.IsSpace(r) || strings.ContainsRune(`();"`, r) ||
+			(i+1 < len(runes) && (r == '&' && runes[i+1] == '&' || r == '|' && runes[i+1] == '|'))
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, filterToken{filterTokenLeft, "(", i, i + 1})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, filterToken{filterTokenRight, ")", i, i + 1})
+			i++
+
+		case r == ';':
+			tokens = append(tokens, filterToken{filterTokenOr, ";", i, i + 1})
+			i++
+
+		case r == '&' && i+1 < len(runes) && runes[i+1] == '&':
+			tokens = append(tokens, filterToken{filterTokenAnd, "&&", i, i + 2})
+			i += 2
+
+		case r == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			tokens = append(tokens, filterToken{filterTokenOr, "||", i, i + 2})
+			i += 2
+
+		case r == '!' && (i+1 >= len(runes) || runes[i+1] != '='):
+			tokens = append(tokens, filterToken{filterTokenNot, "!", i, i + 1})
+			i++
+
+		case r == '"':
+			text := &strings.Builder{}
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				text.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, &FilterSyntaxError{i, "missing closing quote"}
+			}
+			tokens = append(tokens, filterToken{filterTokenQuoted, text.String(), i, j + 1})
+			i = j + 1
+
+		default:
+			j := i + 1
+			for j < len(runes) && !isDelimiter(j) {
+				j++
+			}
+
+			word := string(runes[i:j])
+			kind := filterTokenWord
+			switch word {
+			case "AND":
+				kind = filterTokenAnd
+			case "OR":
+				kind = filterTokenOr
+			case "NOT":
+				kind = filterTokenNot
+			}
+			tokens = append(tokens, filterToken{kind, word, i, j})
+			i = j
+		}
+	}
+
+	return append(tokens, filterToken{filterTokenEnd, "", len(runes), len(runes)}), nil
+}
+
+// splitFilterAlternatives splits the query at the ";" outside of parentheses and quotes
+func splitFilterAlternatives(query string) []string {
+	parts := []string{}
+	depth := 0
+	quoted := false
+	start := 0
+
+	add := func(part string) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case '(':
+			if !quoted {
+				depth++
+			}
+		case ')':
+			if !quoted {
+				depth--
+			}
+		case ';':
+			if !quoted && depth == 0 {
+				add(query[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(query[start:])
+
+	return parts
+}
+
+func trimFilterRunes(text []filterRune) []filterRune {
+	for len(text) > 0 && !text[0].quoted && unicode.IsSpace(text[0].r) {
+		text = text[1:]
+	}
+	for len(text) > 0 && !text[len(text)-1].quoted && unicode.IsSpace(text[len(text)-1].r) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// filterPattern compiles the text to a regular expression matching it completely, unquoted "*" and "?" are wildcards
+func filterPattern(text []filterRune) (*regexp.Regexp, bool) {
+	pattern := &strings.Builder{}
+	wildcard := false
+
+	pattern.WriteString("^")
+	for _, r := range text {
+		switch {
+		case !r.quoted && r.r == '*':
+			pattern.WriteString(".*")
+			wildcard = true
+		case !r.quoted && r.r == '?':
+			pattern.WriteString(".")
+			wildcard = true
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(r.r)))
+		}
+	}
+	pattern.WriteString("$")
+
+	return regexp.MustCompile(pattern.String()), wildcard
+}
+
+func parseFilterNumbers(a, b string) (float64, float64, bool) {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	return x, y, errA == nil && errB == nil
+}
+
+// parseFilterDates parses the dates at the start of both values, e.g. of "2024-05-01 Wednesday"
+func parseFilterDates(a, b string) (time.Time, time.Time, bool) {
+	x, okA := parseFilterDate(a)
+	y, okB := parseFilterDate(b)
+	return x, y, okA && okB
+}
+
+func parseFilterDate(value string) (time.Time, bool) {
+	fields := strings.Fields(value)
+	if len(fields) < 1 {
+		return time.Time{}, false
+	}
+
+	for _, format := range filterDateFormats {
+		if date, err := time.ParseInLocation(format, fields[0], time.Local); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
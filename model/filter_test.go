@@ -0,0 +1,127 @@
+package model
+
+/* This file contains the tests of the tag filter query language */
+
+/* ================================================================================ Imports */
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+/* ================================================================================ Public functions */
+func TestFilterMatches(t *testing.T) {
+	item := &Item{
+		Title: "Item",
+		Tags:  []Tag{{"project=alpha"}, {"status=blocked"}, {"prio=3"}, {"urgent"}, {"owner=two words"}, {"a AND b"}},
+		Due:   time.Date(2025, 1, 10, 0, 0, 0, 0, time.Local),
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"; ", true},
+		{"project=alpha", true},
+		{"project=beta", false},
+		{"project = alpha", true},
+		{"project=al*", true},
+		{"project=?lpha", true},
+		{"project=alp", false},
+		{"project", true},
+		{"urgent", true},
+		{"later", false},
+		{"NOT later", true},
+		{"project=alpha AND NOT status=blocked", false},
+		{"project=alpha && !status=open", true},
+		{"project=beta OR urgent", true},
+		{"project=beta || later", false},
+		{"later; urgent", true},
+		{"later; ", false},
+		{"(prio>5 OR urgent) AND project=alpha", true},
+		{"(prio>5 OR later) project=alpha", false},
+		{"prio>=3", true},
+		{"prio>3", false},
+		{"prio<=3.0", true},
+		{"prio=3.0", true},
+		{"prio!=3", false},
+		{"prio<x", false},
+		{"owner=two words", true},
+		{"owner=two", false},
+		{`"a AND b"`, true},
+		{`status="blocked"`, true},
+		{`project="al*"`, false},
+		{"due<2025-01-11", true},
+		{"due>2025-01-10", false},
+		{"due=2025/01/10", true},
+		{"due>=today", false},
+		{"start", false},
+	}
+
+	for _, test := range tests {
+		filter, err := ParseFilter(test.query)
+		if err != nil {
+			t.Errorf("ParseFilter(%q) failed: %v", test.query, err)
+			continue
+		}
+		if got := filter.Matches(item); got != test.want {
+			t.Errorf("ParseFilter(%q).Matches() = %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestFilterSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+		message  string
+	}{
+		{`"abc`, 0, "missing closing quote"},
+		{`a OR "b`, 5, "missing closing quote"},
+		{"(a OR b", 0, "missing closing parenthesis"},
+		{"a OR (b AND c", 5, "missing closing parenthesis"},
+		{"a)", 1, `unexpected ")"`},
+		{"a AND", 2, `expected a tag after "AND"`},
+		{"a AND NOT", 6, `expected a tag after "NOT"`},
+		{"(", 0, `expected a tag after "("`},
+		{"a AND )", 6, `expected a tag before ")"`},
+		{"AND a", 0, `expected a tag before "AND"`},
+		{"a OR =b", 5, `missing tag name before "="`},
+		{"prio >=", 0, `missing value after ">="`},
+		{"a AND prio<", 6, `missing value after "<"`},
+	}
+
+	for _, test := range tests {
+		_, err := ParseFilter(test.query)
+
+		syntaxError := &FilterSyntaxError{}
+		if !errors.As(err, &syntaxError) {
+			t.Errorf("ParseFilter(%q) error = %v, want a syntax error", test.query, err)
+			continue
+		}
+		if syntaxError.Position != test.position || syntaxError.Message != test.message {
+			t.Errorf("ParseFilter(%q) error = %q at %d, want %q at %d", test.query, syntaxError.Message, syntaxError.Position, test.message, test.position)
+		}
+	}
+}
+
+func TestToggleFilterTag(t *testing.T) {
+	tests := []struct {
+		query string
+		tag   Tag
+		want  string
+	}{
+		{"", Tag{"urgent"}, "urgent; "},
+		{"urgent; ", Tag{"urgent"}, ""},
+		{"later; urgent; ", Tag{"urgent"}, "later; "},
+		{"later", Tag{"a AND b"}, `later; "a AND b"; `},
+		{`(x; y); "a AND b"; `, Tag{"a AND b"}, "(x; y); "},
+	}
+
+	for _, test := range tests {
+		if got := ToggleFilterTag(test.query, test.tag); got != test.want {
+			t.Errorf("ToggleFilterTag(%q, %q) = %q, want %q", test.query, test.tag.Expression, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package model
+
+/* History is a type recording executed board commands to be able to undo and redo them */
+
+/* ================================================================================ Imports */
+import (
+	"errors"
+)
+
+/* ================================================================================ Constants */
+const (
+	HISTORY_LIMIT = 100
+)
+
+/* ================================================================================ Public variables */
+var ErrNothingToUndo = errors.New("nothing to undo")
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+/* ================================================================================ Public types */
+// Command is a reversible change of a board, Undo must exactly restore the state before Do
+type Command interface {
+	Do(b *Board) error
+	Undo(b *Board) error
+}
+
+type History struct {
+	Limit int
+	undo  []Command
+	redo  []Command
+}
+
+/* ================================================================================ Public functions */
+func NewHistory() *History {
+	return &History{Limit: HISTORY_LIMIT}
+}
+
+/* ================================================================================ Public methods */
+func (h *History) Clear() {
+	h.undo = nil
+	h.redo = nil
+}
+
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+// Execute runs the command on the board and records it, which discards all commands that could have been redone
+func (h *History) Execute(b *Board, command Command) error {
+	if err := command.Do(b); err != nil {
+		return err
+	}
+
+	h.undo = append(h.undo, command)
+	if h.Limit > 0 && len(h.undo) > h.Limit {
+		h.undo = h.undo[len(h.undo)-h.Limit:]
+	}
+	h.redo = nil
+
+	return nil
+}
+
+func (h *History) Undo(b *Board) error {
+	if !h.CanUndo() {
+		return ErrNothingToUndo
+	}
+
+	command := h.undo[len(h.undo)-1]
+	if err := command.Undo(b); err != nil {
+		return err
+	}
+
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, command)
+
+	return nil
+}
+
+func (h *History) Redo(b *Board) error {
+	if !h.CanRedo() {
+		return ErrNothingToRedo
+	}
+
+	command := h.redo[len(h.redo)-1]
+	if err := command.Do(b); err != nil {
+		return err
+	}
+
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, command)
+
+	return nil
+}
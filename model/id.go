@@ -0,0 +1,19 @@
+package model
+
+/* This file contains the generation of identifiers to refer to items and stages across saves and moves */
+
+/* ================================================================================ Imports */
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+/* ================================================================================ Public functions */
+func NewID() string {
+	buffer := make([]byte, 8)
+	if _, err := rand.Read(buffer); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(buffer)
+}
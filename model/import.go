@@ -0,0 +1,614 @@
+package model
+
+/* This file contains the import of boards exported by other kanban apps - Trello, my-personal-kanban and Kanbanapp:
+   their lists or columns become stages, cards become items, labels become tags and card colors become item styles,
+   everything without counterpart on a board is left out and listed in the import report */
+
+/* ================================================================================ Imports */
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* ================================================================================ Public variables */
+var ErrUnknownImportFormat = errors.New("unknown import format")
+var ErrUnrecognizedImportFormat = errors.New("unrecognized import format")
+
+/* ================================================================================ Public types */
+type ImportFormat string
+
+const (
+	ImportBanKan           ImportFormat = "bankan"
+	ImportTrello           ImportFormat = "trello"
+	ImportMyPersonalKanban ImportFormat = "mpk"
+	ImportKanbanapp        ImportFormat = "kanbanapp"
+)
+
+// ImportReport summarizes an import, Unmapped lists what could not be taken over to the board
+type ImportReport struct {
+	Format   ImportFormat
+	Stages   int
+	Items    int
+	Unmapped []string
+}
+
+/* ================================================================================ Private types */
+type trelloBoard struct {
+	Name         string            `json:"name"`
+	Lists        []trelloList      `json:"lists"`
+	Cards        []trelloCard      `json:"cards"`
+	Labels       []trelloLabel     `json:"labels"`
+	Checklists   []trelloChecklist `json:"checklists"`
+	Actions      []trelloAction    `json:"actions"`
+	CustomFields []any             `json:"customFields"`
+}
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloCard struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Desc        string   `json:"desc"`
+	IDList      string   `json:"idList"`
+	IDLabels    []string `json:"idLabels"`
+	IDMembers   []string `json:"idMembers"`
+	Closed      bool     `json:"closed"`
+	Pos         float64  `json:"pos"`
+	Start       string   `json:"start"`
+	Due         string   `json:"due"`
+	Attachments []any    `json:"attachments"`
+	Cover       struct {
+		Color string `json:"color"`
+	} `json:"cover"`
+}
+
+type trelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloChecklist struct {
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+type trelloAction struct {
+	Type string `json:"type"`
+	Data struct {
+		Card struct {
+			ID string `json:"id"`
+		} `json:"card"`
+	} `json:"data"`
+}
+
+// mpkStorage is the local storage of my-personal-kanban, which holds all kanbans of the app
+type mpkStorage struct {
+	Kanbans  map[string]mpkKanban `json:"kanbans"`
+	LastUsed string               `json:"lastUsed"`
+}
+
+type mpkKanban struct {
+	Name     string      `json:"name"`
+	Columns  []mpkColumn `json:"columns"`
+	Archived []mpkCard   `json:"archived"`
+}
+
+type mpkColumn struct {
+	Name     string    `json:"name"`
+	Cards    []mpkCard `json:"cards"`
+	Settings struct {
+		Limit any `json:"limit"`
+	} `json:"settings"`
+}
+
+type mpkCard struct {
+	Name    string `json:"name"`
+	Details string `json:"details"`
+	Color   string `json:"color"`
+}
+
+// kanbanappFile is the JSON file of Kanbanapp, which holds one or several boards of lists of cards
+type kanbanappFile struct {
+	Boards []kanbanappBoard `json:"boards"`
+	kanbanappBoard
+}
+
+type kanbanappBoard struct {
+	Name  string          `json:"name"`
+	Title string          `json:"title"`
+	Lists []kanbanappList `json:"lists"`
+}
+
+type kanbanappList struct {
+	Name  string          `json:"name"`
+	Title string          `json:"title"`
+	Cards []kanbanappCard `json:"cards"`
+}
+
+type kanbanappCard struct {
+	Text        string `json:"text"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+}
+
+/* ================================================================================ Private variables */
+// importFormatKeys holds the top-level keys identifying the JSON of each format, one of the key sets has to be complete
+var importFormatKeys = map[ImportFormat][][]string{
+	ImportBanKan:           {{"Stages"}, {"Version"}},
+	ImportTrello:           {{"lists", "cards"}},
+	ImportMyPersonalKanban: {{"kanbans"}, {"columns"}},
+	ImportKanbanapp:        {{"boards"}, {"lists"}},
+}
+
+// trelloColors holds the colors of the Trello labels and card covers, the "_dark" and "_light" variants are mapped to
+// their base color
+var trelloColors = map[string]color.RGBA{
+	"green":  {75, 206, 151, 255},
+	"yellow": {245, 205, 71, 255},
+	"orange": {254, 163, 98, 255},
+	"red":    {248, 113, 104, 255},
+	"purple": {159, 143, 239, 255},
+	"blue":   {87, 157, 255, 255},
+	"sky":    {108, 195, 224, 255},
+	"lime":   {148, 199, 72, 255},
+	"pink":   {232, 116, 187, 255},
+	"black":  {140, 155, 171, 255},
+}
+
+/* ================================================================================ Public functions */
+// ImportFormats returns all formats, the JSON of the app first
+func ImportFormats() []ImportFormat {
+	return []ImportFormat{ImportBanKan, ImportTrello, ImportMyPersonalKanban, ImportKanbanapp}
+}
+
+func ParseImportFormat(name string) (ImportFormat, error) {
+	switch strings.ToLower(name) {
+	case "bankan", "json":
+		return ImportBanKan, nil
+	case "trello":
+		return ImportTrello, nil
+	case "mpk", "my-personal-kanban":
+		return ImportMyPersonalKanban, nil
+	case "kanbanapp":
+		return ImportKanbanapp, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownImportFormat, name)
+	}
+}
+
+// DetectImportFormat returns the format of the JSON data by its top-level keys, JSON of no known structure is refused
+func DetectImportFormat(data []byte) (ImportFormat, error) {
+	document := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return "", err
+	}
+
+	/* Trello exports have lists as well, so they are checked before Kanbanapp */
+	for _, format := range []ImportFormat{ImportTrello, ImportMyPersonalKanban, ImportKanbanapp, ImportBanKan} {
+		if importFormatMatches(document, format) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%w, expected the JSON of BanKan, Trello, my-personal-kanban or Kanbanapp", ErrUnrecognizedImportFormat)
+}
+
+// Import returns the board of the data in the given format, which is detected if empty
+func Import(data []byte, format ImportFormat) (*Board, *ImportReport, error) {
+	if format == "" {
+		var err error
+		if format, err = DetectImportFormat(data); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		document := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, nil, err
+		}
+		if slices.Contains(ImportFormats(), format) && !importFormatMatches(document, format) {
+			return nil, nil, fmt.Errorf("%w, the data is no %s JSON", ErrUnrecognizedImportFormat, format.Name())
+		}
+	}
+
+	b := NewBoard("")
+	report := &ImportReport{Format: format}
+
+	var err error
+	switch format {
+	case ImportBanKan:
+		err = importBanKan(b, data, report)
+	case ImportTrello:
+		err = importTrello(b, data, report)
+	case ImportMyPersonalKanban:
+		err = importMyPersonalKanban(b, data, report)
+	case ImportKanbanapp:
+		err = importKanbanapp(b, data, report)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownImportFormat, format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report.Stages = len(b.Stages)
+	for _, stage := range b.Stages {
+		report.Items += len(stage.Items)
+	}
+
+	return b, report, nil
+}
+
+/* ================================================================================ Public methods */
+// Name returns the name of the app the format comes from
+func (f ImportFormat) Name() string {
+	switch f {
+	case ImportTrello:
+		return "Trello"
+	case ImportMyPersonalKanban:
+		return "my-personal-kanban"
+	case ImportKanbanapp:
+		return "Kanbanapp"
+	default:
+		return "BanKan"
+	}
+}
+
+// String returns the summary and the list of the unmapped content
+func (r *ImportReport) String() string {
+	buffer := &strings.Builder{}
+
+	fmt.Fprintf(buffer, "Imported %d stages and %d items from %s.", r.Stages, r.Items, r.Format.Name())
+	if len(r.Unmapped) > 0 {
+		buffer.WriteString("\n\nNot imported:")
+		for _, unmapped := range r.Unmapped {
+			fmt.Fprintf(buffer, "\n- %s", unmapped)
+		}
+	}
+
+	return buffer.String()
+}
+
+/* ================================================================================ Private methods */
+func (r *ImportReport) addUnmapped(format string, args ...any) {
+	r.Unmapped = append(r.Unmapped, fmt.Sprintf(format, args...))
+}
+
+/* ================================================================================ Private functions */
+func importFormatMatches(document map[string]json.RawMessage, format ImportFormat) bool {
+	for _, keys := range importFormatKeys[format] {
+		complete := true
+		for _, key := range keys {
+			if _, found := document[key]; !found {
+				complete = false
+			}
+		}
+		if complete {
+			return true
+		}
+	}
+	return false
+}
+
+// importBanKan loads the board, the filter views, activity log and settings belong to the board imported from, so they
+// are left out
+func importBanKan(b *Board, data []byte, report *ImportReport) error {
+	if err := b.Load(data); err != nil {
+		return err
+	}
+
+	if len(b.Views) > 0 {
+		names := []string{}
+		for _, view := range b.Views {
+			names = append(names, fmt.Sprintf("%q", view.Name))
+		}
+		report.addUnmapped("filter views (%s)", strings.Join(names, ", "))
+		b.Views = nil
+	}
+	if count := len(b.Activities); count > 0 {
+		report.addUnmapped("activity log entries (%d)", count)
+		b.Activities = nil
+	}
+	if b.PlainText {
+		report.addUnmapped("plain text descriptions setting")
+		b.PlainText = false
+	}
+
+	return nil
+}
+
+func importTrello(b *Board, data []byte, report *ImportReport) error {
+	trello := trelloBoard{}
+	if err := json.Unmarshal(data, &trello); err != nil {
+		return err
+	}
+	b.Name = trello.Name
+
+	labels := map[string]trelloLabel{}
+	for _, label := range trello.Labels {
+		labels[label.ID] = label
+	}
+
+	checklists := map[string][]trelloChecklist{}
+	slices.SortStableFunc(trello.Checklists, func(a, b trelloChecklist) int { return cmp.Compare(a.Pos, b.Pos) })
+	for _, checklist := range trello.Checklists {
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], checklist)
+	}
+
+	comments := map[string]int{}
+	for _, action := range trello.Actions {
+		if action.Type == "commentCard" {
+			comments[action.Data.Card.ID]++
+		}
+	}
+
+	slices.SortStableFunc(trello.Lists, func(a, b trelloList) int { return cmp.Compare(a.Pos, b.Pos) })
+	slices.SortStableFunc(trello.Cards, func(a, b trelloCard) int { return cmp.Compare(a.Pos, b.Pos) })
+
+	stages := map[string]*Stage{}
+	for _, list := range trello.Lists {
+		if list.Closed {
+			report.addUnmapped("archived list %q", list.Name)
+			continue
+		}
+		stages[list.ID] = NewStage(list.Name)
+		b.AppendStage(stages[list.ID])
+	}
+
+	labelColors := 0
+	for _, card := range trello.Cards {
+		stage := stages[card.IDList]
+		switch {
+		case card.Closed:
+			report.addUnmapped("archived card %q", card.Name)
+			continue
+		case stage == nil:
+			report.addUnmapped("card %q of an archived or unknown list", card.Name)
+			continue
+		}
+
+		tags := []Tag{}
+		for _, id := range card.IDLabels {
+			label, found := labels[id]
+			if !found {
+				continue
+			}
+			if label.Name == "" {
+				tags = append(tags, Tag{label.Color})
+			} else {
+				tags = append(tags, Tag{importTagExpression(label.Name)})
+			}
+			if label.Color != "" {
+				labelColors++
+			}
+		}
+
+		style := DefaultItemStyle
+		if card.Cover.Color != "" {
+			background, found := trelloColors[strings.TrimSuffix(strings.TrimSuffix(card.Cover.Color, "_dark"), "_light")]
+			if found {
+				style = importItemStyle(background)
+			} else {
+				report.addUnmapped("cover color %q of card %q", card.Cover.Color, card.Name)
+			}
+		}
+
+		item := NewItem(card.Name, tags, card.Desc, style, "Normal")
+		item.SetDates(importTime(card.Start), importTime(card.Due))
+
+		cardChecklists := checklists[card.ID]
+		for _, checklist := range cardChecklists {
+			slices.SortStableFunc(checklist.CheckItems, func(a, b trelloCheckItem) int { return cmp.Compare(a.Pos, b.Pos) })
+			for _, checkItem := range checklist.CheckItems {
+				item.Checklist = append(item.Checklist, ChecklistEntry{Text: checkItem.Name, Done: checkItem.State == "complete"})
+			}
+		}
+		if len(cardChecklists) > 1 {
+			report.addUnmapped("checklist names of card %q, its %d checklists were joined", card.Name, len(cardChecklists))
+		}
+
+		if count := len(card.Attachments); count > 0 {
+			report.addUnmapped("attachments of card %q (%d)", card.Name, count)
+		}
+		if count := comments[card.ID]; count > 0 {
+			report.addUnmapped("comments of card %q (%d)", card.Name, count)
+		}
+		if count := len(card.IDMembers); count > 0 {
+			report.addUnmapped("members of card %q (%d)", card.Name, count)
+		}
+
+		stage.AppendItem(item)
+	}
+
+	if labelColors > 0 {
+		report.addUnmapped("label colors, %d labels became tags without color", labelColors)
+	}
+	if count := len(trello.CustomFields); count > 0 {
+		report.addUnmapped("custom fields (%d)", count)
+	}
+
+	return nil
+}
+
+func importMyPersonalKanban(b *Board, data []byte, report *ImportReport) error {
+	storage := mpkStorage{}
+	if err := json.Unmarshal(data, &storage); err != nil {
+		return err
+	}
+
+	/* The export holds either a single kanban or the whole storage, of which the last used kanban is imported */
+	kanban := mpkKanban{}
+	if storage.Kanbans == nil {
+		if err := json.Unmarshal(data, &kanban); err != nil {
+			return err
+		}
+	} else {
+		names := []string{}
+		for name := range storage.Kanbans {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		name := storage.LastUsed
+		if _, found := storage.Kanbans[name]; !found && len(names) > 0 {
+			name = names[0]
+		}
+		kanban = storage.Kanbans[name]
+
+		for _, other := range names {
+			if other != name {
+				report.addUnmapped("kanban %q, only %q was imported", other, name)
+			}
+		}
+	}
+	b.Name = kanban.Name
+
+	for _, column := range kanban.Columns {
+		stage := NewStage(column.Name)
+		stage.WIPLimit = importNumber(column.Settings.Limit)
+		b.AppendStage(stage)
+
+		for _, card := range column.Cards {
+			style := DefaultItemStyle
+			if card.Color != "" {
+				if background, ok := parseHexColor(card.Color); ok {
+					style = importItemStyle(background)
+				} else {
+					report.addUnmapped("color %q of card %q", card.Color, card.Name)
+				}
+			}
+			stage.AppendItem(NewItem(card.Name, nil, card.Details, style, "Normal"))
+		}
+	}
+
+	if count := len(kanban.Archived); count > 0 {
+		report.addUnmapped("archived cards (%d)", count)
+	}
+
+	return nil
+}
+
+func importKanbanapp(b *Board, data []byte, report *ImportReport) error {
+	file := kanbanappFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	board := file.kanbanappBoard
+	if len(file.Boards) > 0 {
+		board = file.Boards[0]
+		for _, other := range file.Boards[1:] {
+			report.addUnmapped("board %q, only the first board was imported", firstNonEmpty(other.Name, other.Title))
+		}
+	}
+	b.Name = firstNonEmpty(board.Name, board.Title)
+
+	for _, list := range board.Lists {
+		stage := NewStage(firstNonEmpty(list.Name, list.Title))
+		b.AppendStage(stage)
+
+		for _, card := range list.Cards {
+			/* Cards of Kanbanapp are plain text, the first line is taken as title */
+			title, description := firstNonEmpty(card.Title, card.Text), card.Description
+			if card.Title == "" {
+				title, description, _ = strings.Cut(card.Text, "\n")
+				description = strings.TrimSpace(description)
+			}
+
+			style := DefaultItemStyle
+			if card.Color != "" {
+				if background, ok := parseHexColor(card.Color); ok {
+					style = importItemStyle(background)
+				} else {
+					report.addUnmapped("color %q of card %q", card.Color, title)
+				}
+			}
+			stage.AppendItem(NewItem(strings.TrimSpace(title), nil, description, style, "Normal"))
+		}
+	}
+
+	return nil
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// importTagExpression returns the label name as tag expression, the tag separator is not allowed
+func importTagExpression(name string) string {
+	return strings.TrimSpace(strings.ReplaceAll(name, ";", ","))
+}
+
+// importTime returns the calendar day of an ISO 8601 time, the zero time if empty or invalid
+func importTime(text string) time.Time {
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return time.Time{}
+	}
+	return Date(t.Local())
+}
+
+// importNumber returns the number given as JSON number or as numeric string, 0 otherwise
+func importNumber(value any) int {
+	switch value := value.(type) {
+	case float64:
+		return int(value)
+	case string:
+		number, _ := strconv.Atoi(strings.TrimSpace(value))
+		return number
+	default:
+		return 0
+	}
+}
+
+// importItemStyle returns the style with the background color and black or white text, whichever is more readable
+func importItemStyle(background color.RGBA) ItemStyle {
+	foreground := color.RGBA{0, 0, 0, 255}
+	if 299*int(background.R)+587*int(background.G)+114*int(background.B) < 128000 {
+		foreground = color.RGBA{255, 255, 255, 255}
+	}
+	return ItemStyle{Foreground: foreground, Background: background}
+}
+
+// parseHexColor parses a color like "#ff8800", "ff8800" or "#f80"
+func parseHexColor(text string) (color.RGBA, bool) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "#")
+	if len(text) == 3 {
+		text = string([]byte{text[0], text[0], text[1], text[1], text[2], text[2]})
+	}
+	if len(text) != 6 {
+		return color.RGBA{}, false
+	}
+
+	value, err := strconv.ParseUint(text, 16, 32)
+	if err != nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 255}, true
+}
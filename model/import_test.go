@@ -0,0 +1,134 @@
+package model
+
+/* This file contains the tests of the import of boards exported by other kanban apps, with sample exports in testdata */
+
+/* ================================================================================ Imports */
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+/* ================================================================================ Public functions */
+func TestImport(t *testing.T) {
+	tests := []struct {
+		file     string
+		format   ImportFormat
+		name     string
+		layout   string
+		unmapped []string
+	}{
+		{"trello.json", ImportTrello, "Project", "To Do: Fix crash, Write docs | Done: Release", []string{
+			`archived list "Old"`,
+			`archived card "Archived"`,
+			`card "Forgotten" of an archived or unknown list`,
+			`comments of card "Fix crash" (1)`,
+			`members of card "Write docs" (1)`,
+			`attachments of card "Release" (1)`,
+			"label colors, 2 labels became tags without color",
+		}},
+		{"mpk.json", ImportMyPersonalKanban, "Home", "Not started: Paint fence | In progress: Clean garage | Done: ", []string{
+			`kanban "Work", only "Home" was imported`,
+			"archived cards (1)",
+		}},
+		{"kanbanapp.json", ImportKanbanapp, "Shopping", "Need: Milk, Bread | Bought: Eggs", []string{
+			`board "Ideas", only the first board was imported`,
+			`color "mauve" of card "Eggs"`,
+		}},
+		{"bankan.json", ImportBanKan, "Board", "Todo: Task", []string{
+			`filter views ("Urgent")`,
+			"activity log entries (1)",
+			"plain text descriptions setting",
+		}},
+	}
+
+	for _, test := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, report, err := Import(data, "")
+		if err != nil {
+			t.Errorf("%s: Import() failed: %v", test.file, err)
+			continue
+		}
+
+		if report.Format != test.format {
+			t.Errorf("%s: detected format %q, want %q", test.file, report.Format, test.format)
+		}
+		if b.Name != test.name {
+			t.Errorf("%s: board name %q, want %q", test.file, b.Name, test.name)
+		}
+		if got := testBoardLayout(b); got != test.layout {
+			t.Errorf("%s: imported board = %q, want %q", test.file, got, test.layout)
+		}
+		items := 0
+		for _, stage := range b.Stages {
+			items += len(stage.Items)
+		}
+		if report.Stages != len(b.Stages) || report.Items != items {
+			t.Errorf("%s: report counts %d stages and %d items", test.file, report.Stages, report.Items)
+		}
+		for _, unmapped := range test.unmapped {
+			if !slices.Contains(report.Unmapped, unmapped) {
+				t.Errorf("%s: report lacks %q, got %q", test.file, unmapped, report.Unmapped)
+			}
+		}
+		if len(report.Unmapped) != len(test.unmapped) {
+			t.Errorf("%s: report lists %q, want %q", test.file, report.Unmapped, test.unmapped)
+		}
+		if len(b.Views) > 0 || len(b.Activities) > 0 || b.PlainText {
+			t.Errorf("%s: imported board keeps views, activities or settings", test.file)
+		}
+	}
+}
+
+func TestImportTrelloCard(t *testing.T) {
+	data, _ := os.ReadFile(filepath.Join("testdata", "trello.json"))
+	b, _, err := Import(data, ImportTrello)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := b.Stages[0].Items[0]
+	if got := ComposeTagEditString(item.Tags); got != "Bug; green; " {
+		t.Errorf("tags = %q", got)
+	}
+	if FormatDate(item.Start) != "2025-01-06" || FormatDate(item.Due) != "2025-01-10" {
+		t.Errorf("dates = %s - %s", FormatDate(item.Start), FormatDate(item.Due))
+	}
+	if item.Style.Background != trelloColors["yellow"] {
+		t.Errorf("style = %v", item.Style)
+	}
+	if !slices.Equal(item.Checklist, []ChecklistEntry{{"Reproduce", true}, {"Fix", false}}) {
+		t.Errorf("checklist = %v", item.Checklist)
+	}
+}
+
+func TestImportUnrecognized(t *testing.T) {
+	mpk, _ := os.ReadFile(filepath.Join("testdata", "mpk.json"))
+
+	tests := []struct {
+		name   string
+		data   string
+		format ImportFormat
+	}{
+		{"unknown structure", `{"title": "Board", "items": []}`, ""},
+		{"empty object", `{}`, ""},
+		{"forced wrong format", string(mpk), ImportTrello},
+		{"forced BanKan", `{"lists": []}`, ImportBanKan},
+	}
+
+	for _, test := range tests {
+		if _, _, err := Import([]byte(test.data), test.format); !errors.Is(err, ErrUnrecognizedImportFormat) {
+			t.Errorf("%s: Import() error = %v, want %v", test.name, err, ErrUnrecognizedImportFormat)
+		}
+	}
+
+	if _, _, err := Import([]byte(`[1, 2]`), ""); err == nil {
+		t.Errorf("Import() of no JSON object succeeded")
+	}
+}
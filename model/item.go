@@ -0,0 +1,61 @@
+package model
+
+/* Item is the headless type describing an entry inside a stage, which holds the actual task information */
+
+/* ================================================================================ Imports */
+import (
+	"image/color"
+	"time"
+)
+
+/* ================================================================================ Public variables */
+// DefaultItemStyle is the style of newly created items
+var DefaultItemStyle = ItemStyle{Foreground: color.RGBA{0, 0, 0, 255}, Background: color.RGBA{192, 192, 192, 255}}
+
+/* ================================================================================ Public types */
+type ItemStyle struct {
+	Foreground, Background color.RGBA
+}
+
+type Item struct {
+	ID          string
+	Created     time.Time
+	Modified    time.Time
+	Moved       time.Time
+	Title       string
+	Description string
+	Tags        []Tag
+	Style       ItemStyle
+	Expanded    bool
+	DataType    string // 数据类型："Normal", "Gregorian", "Lunar", "Tibetan"
+	LaneID      string
+	Start       time.Time
+	Due         time.Time
+	Recurrence  *Recurrence
+	Checklist   []ChecklistEntry
+}
+
+/* ================================================================================ Public functions */
+func NewItem(title string, tags []Tag, description string, style ItemStyle, dataType string) *Item {
+	now := time.Now()
+	return &Item{ID: NewID(), Created: now, Modified: now, Title: title, Tags: tags, Description: description, Style: style, Expanded: false, DataType: dataType}
+}
+
+/* ================================================================================ Public methods */
+func (i *Item) Update(title string, tags []Tag, description string, style ItemStyle, dataType string) {
+	i.Title = title
+	i.Tags = tags
+	i.Description = description
+	i.Style = style
+	i.DataType = dataType
+	i.Modified = time.Now()
+}
+
+func (i *Item) HasTag(toFind Tag) bool {
+	for _, tag := range i.Tags {
+		if tag == toFind {
+			return true
+		}
+	}
+	return false
+}
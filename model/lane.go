@@ -0,0 +1,101 @@
+package model
+
+/* Lane is the headless type describing a swimlane, a horizontal group spanning all stages of a board - items are
+   assigned to a lane by its ID, items without a (known) lane belong to the first lane */
+
+/* ================================================================================ Public types */
+type Lane struct {
+	ID        string
+	Title     string
+	Collapsed bool
+}
+
+/* ================================================================================ Public functions */
+func NewLane(title string) *Lane {
+	return &Lane{ID: NewID(), Title: title}
+}
+
+/* ================================================================================ Public methods */
+func (b *Board) LaneIndex(toFind *Lane) int {
+	for i, lane := range b.Lanes {
+		if lane == toFind {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *Board) LaneByID(id string) *Lane {
+	for _, lane := range b.Lanes {
+		if lane.ID == id {
+			return lane
+		}
+	}
+	return nil
+}
+
+func (b *Board) LaneByTitle(title string) *Lane {
+	for _, lane := range b.Lanes {
+		if lane.Title == title {
+			return lane
+		}
+	}
+	return nil
+}
+
+// ItemLane returns the lane the item belongs to, which is the first lane for items without a known lane, or nil if
+// the board has no lanes
+func (b *Board) ItemLane(item *Item) *Lane {
+	if len(b.Lanes) < 1 {
+		return nil
+	}
+
+	if lane := b.LaneByID(item.LaneID); lane != nil {
+		return lane
+	}
+	return b.Lanes[0]
+}
+
+// LaneItems returns the items of the stage belonging to the lane, in the order of the stage
+func (b *Board) LaneItems(stage *Stage, lane *Lane) []*Item {
+	items := []*Item{}
+
+	for _, item := range stage.Items {
+		if b.ItemLane(item) == lane {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// InsertLane inserts the lane before the given index, an index out of range appends it
+func (b *Board) InsertLane(index int, lane *Lane) {
+	if index < 0 || index >= len(b.Lanes) {
+		b.Lanes = append(b.Lanes, lane)
+		return
+	}
+
+	b.Lanes = append(b.Lanes, nil)
+	copy(b.Lanes[index+1:], b.Lanes[index:])
+	b.Lanes[index] = lane
+}
+
+func (b *Board) RemoveLane(toRemove *Lane) bool {
+	i := b.LaneIndex(toRemove)
+	if i < 0 {
+		return false
+	}
+
+	b.Lanes = append(b.Lanes[:i], b.Lanes[i+1:]...)
+	return true
+}
+
+// MoveLane moves the lane before the given index (counted without the lane itself), an index out of range appends it
+func (b *Board) MoveLane(lane *Lane, index int) error {
+	if !b.RemoveLane(lane) {
+		return ErrLaneNotFound
+	}
+
+	b.InsertLane(index, lane)
+	return nil
+}
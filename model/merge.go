@@ -0,0 +1,496 @@
+package model
+
+/* This file contains the three-way merge of boards, which combines the changes of two edited copies of a common base
+   on the level of stages and items, identified by their IDs */
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+/* ================================================================================ Constants */
+const (
+	CONFLICT_TAG = "Conflict"
+)
+
+/* ================================================================================ Public types */
+// Conflict describes a change made on both sides which could not be combined, the merged board keeps the value of mine
+type Conflict struct {
+	StageID string
+	ItemID  string
+	Title   string
+	Field   string
+	Mine    string
+	Theirs  string
+}
+
+/* ================================================================================ Private types */
+type itemVersion struct {
+	item    *Item
+	stageID string
+}
+
+type boardIndex struct {
+	stageIDs []string
+	stages   map[string]*Stage
+	items    map[string]itemVersion
+	itemIDs  map[string][]string
+}
+
+type merger struct {
+	base, mine, theirs boardIndex
+	conflicts          []Conflict
+}
+
+/* ================================================================================ Public functions */
+// Merge combines the changes from base to mine and from base to theirs into a new board, the input boards are not modified
+func Merge(base, mine, theirs *Board) (*Board, []Conflict) {
+	if base == nil {
+		base = &Board{}
+	}
+
+	m := &merger{base: newBoardIndex(base), mine: newBoardIndex(mine), theirs: newBoardIndex(theirs)}
+	merged := &Board{Version: SCHEMA_VERSION}
+
+	merged.Name = mergeField(m, "", "", base.Name, "Name", base.Name, mine.Name, theirs.Name, true)
+	merged.PlainText = mergeField(m, "", "", base.Name, "Plain text", base.PlainText, mine.PlainText, theirs.PlainText, true)
+	merged.Views = m.mergeViews(base.Views, mine.Views, theirs.Views)
+	merged.Activities = mergeActivities(mine.Activities, theirs.Activities)
+	merged.Lanes = m.mergeLanes(base.Lanes, mine.Lanes, theirs.Lanes)
+
+	/* Merge the items first, as a stage removed on one side has to be kept if it still holds items */
+	items := map[string]*Item{}
+	itemStageIDs := map[string]string{}
+	holdsItems := map[string]bool{}
+
+	for _, id := range unionIDs(m.base.itemOrder(), m.mine.itemOrder(), m.theirs.itemOrder()) {
+		if item, stageID := m.mergeItem(id); item != nil {
+			items[id] = item
+			itemStageIDs[id] = stageID
+			holdsItems[stageID] = true
+		}
+	}
+
+	stages := map[string]*Stage{}
+	for _, id := range unionIDs(m.base.stageIDs, m.mine.stageIDs, m.theirs.stageIDs) {
+		if stage := m.mergeStage(id, holdsItems[id]); stage != nil {
+			stages[id] = stage
+		}
+	}
+
+	for _, id := range mergeOrder(m.base.stageIDs, m.mine.stageIDs, m.theirs.stageIDs, keys(stages)) {
+		stage := stages[id]
+
+		members := []string{}
+		for _, itemID := range unionIDs(m.base.itemIDs[id], m.mine.itemIDs[id], m.theirs.itemIDs[id], keys(items)) {
+			if itemStageIDs[itemID] == id {
+				members = append(members, itemID)
+			}
+		}
+
+		for _, itemID := range mergeOrder(m.base.itemIDs[id], m.mine.itemIDs[id], m.theirs.itemIDs[id], members) {
+			stage.Items = append(stage.Items, items[itemID])
+		}
+		merged.Stages = append(merged.Stages, stage)
+	}
+
+	return merged, m.conflicts
+}
+
+// MarkConflicts tags the items of the merged board affected by conflicts and notes the discarded value of theirs in
+// the description, so they can be found with the tag filter and resolved by hand
+func MarkConflicts(b *Board, conflicts []Conflict) {
+	for _, conflict := range conflicts {
+		item := b.ItemByID(conflict.ItemID)
+		if item == nil {
+			continue
+		}
+
+		tag := Tag{CONFLICT_TAG + "=" + conflict.Field}
+		if !item.HasTag(tag) {
+			item.Tags = append(item.Tags, tag)
+		}
+
+		if conflict.Field != "Removed" {
+			if item.Description != "" {
+				item.Description += "\n"
+			}
+			item.Description += fmt.Sprintf("%s of theirs: %s", conflict.Field, conflict.Theirs)
+		}
+	}
+}
+
+/* ================================================================================ Public methods */
+func (c Conflict) String() string {
+	subject := "Board"
+	switch {
+	case c.ItemID != "":
+		subject = fmt.Sprintf("Item %q", c.Title)
+	case c.StageID != "":
+		subject = fmt.Sprintf("Stage %q", c.Title)
+	}
+
+	if c.Field == "Removed" {
+		return fmt.Sprintf("%s: %s in mine, %s in theirs", subject, c.Mine, c.Theirs)
+	}
+	return fmt.Sprintf("%s: %s changed on both sides (mine: %q, theirs: %q)", subject, c.Field, c.Mine, c.Theirs)
+}
+
+/* ================================================================================ Private functions */
+func newBoardIndex(b *Board) boardIndex {
+	index := boardIndex{stages: map[string]*Stage{}, items: map[string]itemVersion{}, itemIDs: map[string][]string{}}
+
+	for _, stage := range b.Stages {
+		index.stageIDs = append(index.stageIDs, stage.ID)
+		index.stages[stage.ID] = stage
+
+		for _, item := range stage.Items {
+			index.items[item.ID] = itemVersion{item, stage.ID}
+			index.itemIDs[stage.ID] = append(index.itemIDs[stage.ID], item.ID)
+		}
+	}
+	return index
+}
+
+func (index boardIndex) itemOrder() []string {
+	ids := []string{}
+	for _, stageID := range index.stageIDs {
+		ids = append(ids, index.itemIDs[stageID]...)
+	}
+	return ids
+}
+
+func unionIDs(lists ...[]string) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+
+	for _, list := range lists {
+		for _, id := range list {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func keys[V any](values map[string]V) []string {
+	ids := make([]string, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// mergeField merges a single value, changes of only one side are taken over, a change on both sides is a conflict
+func mergeField[T comparable](m *merger, stageID, itemID, title, field string, base, mine, theirs T, hasBase bool) T {
+	return mergeFieldFunc(m, stageID, itemID, title, field, base, mine, theirs, hasBase, func(a, b T) bool { return a == b })
+}
+
+func mergeFieldFunc[T any](m *merger, stageID, itemID, title, field string, base, mine, theirs T, hasBase bool, equal func(a, b T) bool) T {
+	switch {
+	case equal(mine, theirs):
+		return mine
+	case hasBase && equal(base, mine):
+		return theirs
+	case hasBase && equal(base, theirs):
+		return mine
+	}
+
+	m.conflict(stageID, itemID, title, field, fmt.Sprint(mine), fmt.Sprint(theirs))
+	return mine
+}
+
+// mergeOrder orders the members like the side which changed the order, members missing there are placed after their predecessor on the other side
+func mergeOrder(base, mine, theirs, members []string) []string {
+	primary, secondary := mine, theirs
+	if slices.Equal(mine, base) {
+		primary, secondary = theirs, mine
+	}
+
+	isMember := map[string]bool{}
+	for _, id := range members {
+		isMember[id] = true
+	}
+
+	order := []string{}
+	for _, id := range primary {
+		if isMember[id] && !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
+
+	for i, id := range secondary {
+		if !isMember[id] || slices.Contains(order, id) {
+			continue
+		}
+
+		position := 0
+		for j := i - 1; j >= 0; j-- {
+			if k := slices.Index(order, secondary[j]); k >= 0 {
+				position = k + 1
+				break
+			}
+		}
+		order = slices.Insert(order, position, id)
+	}
+
+	for _, id := range members {
+		if !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
+	return order
+}
+
+func cloneItem(item *Item) *Item {
+	clone := *item
+	clone.Tags = slices.Clone(item.Tags)
+	clone.Checklist = slices.Clone(item.Checklist)
+	clone.Recurrence = item.Recurrence.Clone()
+	return &clone
+}
+
+func cloneStage(stage *Stage) *Stage {
+	clone := *stage
+	clone.Items = nil
+	return &clone
+}
+
+func itemVersionEqual(a, b itemVersion) bool {
+	return a.stageID == b.stageID && a.item.Title == b.item.Title && a.item.Description == b.item.Description &&
+		slices.Equal(a.item.Tags, b.item.Tags) && a.item.Style == b.item.Style && a.item.DataType == b.item.DataType &&
+		a.item.LaneID == b.item.LaneID && a.item.Start.Equal(b.item.Start) && a.item.Due.Equal(b.item.Due) &&
+		RecurrenceEqual(a.item.Recurrence, b.item.Recurrence) && slices.Equal(a.item.Checklist, b.item.Checklist)
+}
+
+func stageEqual(a, b *Stage) bool {
+	return a.Title == b.Title && a.WIPLimit == b.WIPLimit
+}
+
+/* ================================================================================ Private methods */
+func (m *merger) conflict(stageID, itemID, title, field, mine, theirs string) {
+	m.conflicts = append(m.conflicts, Conflict{stageID, itemID, title, field, mine, theirs})
+}
+
+func (m *merger) mergeItem(id string) (*Item, string) {
+	base, inBase := m.base.items[id]
+	mine, inMine := m.mine.items[id]
+	theirs, inTheirs := m.theirs.items[id]
+
+	switch {
+	case inMine && inTheirs:
+		return m.mergeItemFields(id, base, mine, theirs, inBase)
+
+	case inMine && !inBase:
+		return cloneItem(mine.item), mine.stageID
+
+	case inTheirs && !inBase:
+		return cloneItem(theirs.item), theirs.stageID
+
+	case inMine:
+		/* Removed by them, kept if changed by me */
+		if !itemVersionEqual(base, mine) {
+			m.conflict(mine.stageID, id, mine.item.Title, "Removed", "changed", "removed")
+			return cloneItem(mine.item), mine.stageID
+		}
+
+	case inTheirs:
+		/* Removed by me, kept if changed by them */
+		if !itemVersionEqual(base, theirs) {
+			m.conflict(theirs.stageID, id, theirs.item.Title, "Removed", "removed", "changed")
+			return cloneItem(theirs.item), theirs.stageID
+		}
+	}
+
+	return nil, ""
+}
+
+func (m *merger) mergeItemFields(id string, base, mine, theirs itemVersion, hasBase bool) (*Item, string) {
+	if !hasBase {
+		base = itemVersion{&Item{}, ""}
+	}
+
+	title := mine.item.Title
+	merged := cloneItem(mine.item)
+
+	stageID := mergeField(m, mine.stageID, id, title, "Stage", base.stageID, mine.stageID, theirs.stageID, hasBase)
+	merged.Title = mergeField(m, stageID, id, title, "Title", base.item.Title, mine.item.Title, theirs.item.Title, hasBase)
+	merged.Description = mergeField(m, stageID, id, title, "Description", base.item.Description, mine.item.Description, theirs.item.Description, hasBase)
+	merged.Tags = slices.Clone(mergeFieldFunc(m, stageID, id, title, "Tags", base.item.Tags, mine.item.Tags, theirs.item.Tags, hasBase, slices.Equal[[]Tag]))
+	merged.Style = mergeField(m, stageID, id, title, "Style", base.item.Style, mine.item.Style, theirs.item.Style, hasBase)
+	merged.DataType = mergeField(m, stageID, id, title, "DataType", base.item.DataType, mine.item.DataType, theirs.item.DataType, hasBase)
+	merged.LaneID = mergeField(m, stageID, id, title, "Lane", base.item.LaneID, mine.item.LaneID, theirs.item.LaneID, hasBase)
+	merged.Start = mergeFieldFunc(m, stageID, id, title, "Start", base.item.Start, mine.item.Start, theirs.item.Start, hasBase, time.Time.Equal)
+	merged.Due = mergeFieldFunc(m, stageID, id, title, "Due", base.item.Due, mine.item.Due, theirs.item.Due, hasBase, time.Time.Equal)
+	merged.Recurrence = mergeFieldFunc(m, stageID, id, title, "Recurrence", base.item.Recurrence, mine.item.Recurrence, theirs.item.Recurrence, hasBase, RecurrenceEqual).Clone()
+	merged.Checklist = slices.Clone(mergeFieldFunc(m, stageID, id, title, "Checklist", base.item.Checklist, mine.item.Checklist, theirs.item.Checklist, hasBase, slices.Equal))
+
+	/* The expanded state is only a view setting, so differences are never reported */
+	if hasBase && mine.item.Expanded == base.item.Expanded {
+		merged.Expanded = theirs.item.Expanded
+	}
+
+	if theirs.item.Modified.After(merged.Modified) {
+		merged.Modified = theirs.item.Modified
+	}
+	if theirs.item.Moved.After(merged.Moved) {
+		merged.Moved = theirs.item.Moved
+	}
+
+	return merged, stageID
+}
+
+// mergeViews merges the filter views by name, a view removed on one side and changed on the other is kept
+func (m *merger) mergeViews(base, mine, theirs []FilterView) []FilterView {
+	queries := func(views []FilterView) ([]string, map[string]string) {
+		names := []string{}
+		byName := map[string]string{}
+		for _, view := range views {
+			names = append(names, view.Name)
+			byName[view.Name] = view.Query
+		}
+		return names, byName
+	}
+
+	baseNames, baseQueries := queries(base)
+	mineNames, mineQueries := queries(mine)
+	theirNames, theirQueries := queries(theirs)
+
+	merged := map[string]string{}
+	for _, name := range unionIDs(baseNames, mineNames, theirNames) {
+		baseQuery, inBase := baseQueries[name]
+		mineQuery, inMine := mineQueries[name]
+		theirQuery, inTheirs := theirQueries[name]
+
+		switch {
+		case inMine && inTheirs:
+			merged[name] = mergeField(m, "", "", name, fmt.Sprintf("Query of view %q", name), baseQuery, mineQuery, theirQuery, inBase)
+		case inMine && (!inBase || mineQuery != baseQuery):
+			merged[name] = mineQuery
+		case inTheirs && (!inBase || theirQuery != baseQuery):
+			merged[name] = theirQuery
+		}
+	}
+
+	views := []FilterView{}
+	for _, name := range mergeOrder(baseNames, mineNames, theirNames, keys(merged)) {
+		views = append(views, FilterView{name, merged[name]})
+	}
+	return views
+}
+
+// mergeActivities joins the activity logs by ID in chronological order, as both sides only appended to their log
+func mergeActivities(logs ...[]Activity) []Activity {
+	merged := []Activity{}
+	seen := map[string]bool{}
+
+	for _, log := range logs {
+		for _, activity := range log {
+			if !seen[activity.ID] {
+				seen[activity.ID] = true
+				merged = append(merged, activity)
+			}
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b Activity) int { return a.Time.Compare(b.Time) })
+
+	return merged
+}
+
+// mergeLanes merges the lanes by ID, a lane removed on one side and renamed on the other is kept
+func (m *merger) mergeLanes(base, mine, theirs []*Lane) []*Lane {
+	index := func(lanes []*Lane) ([]string, map[string]*Lane) {
+		ids := []string{}
+		byID := map[string]*Lane{}
+		for _, lane := range lanes {
+			ids = append(ids, lane.ID)
+			byID[lane.ID] = lane
+		}
+		return ids, byID
+	}
+
+	baseIDs, baseLanes := index(base)
+	mineIDs, mineLanes := index(mine)
+	theirIDs, theirLanes := index(theirs)
+
+	merged := map[string]*Lane{}
+	for _, id := range unionIDs(baseIDs, mineIDs, theirIDs) {
+		baseLane, inBase := baseLanes[id]
+		mineLane, inMine := mineLanes[id]
+		theirLane, inTheirs := theirLanes[id]
+		if !inBase {
+			baseLane = &Lane{}
+		}
+
+		switch {
+		case inMine && inTheirs:
+			lane := *mineLane
+			lane.Title = mergeField(m, "", "", mineLane.Title, "Title of lane", baseLane.Title, mineLane.Title, theirLane.Title, inBase)
+			/* The collapsed state is only a view setting, so differences are never reported */
+			if inBase && mineLane.Collapsed == baseLane.Collapsed {
+				lane.Collapsed = theirLane.Collapsed
+			}
+			merged[id] = &lane
+		case inMine && (!inBase || mineLane.Title != baseLane.Title):
+			lane := *mineLane
+			merged[id] = &lane
+		case inTheirs && (!inBase || theirLane.Title != baseLane.Title):
+			lane := *theirLane
+			merged[id] = &lane
+		}
+	}
+
+	lanes := []*Lane{}
+	for _, id := range mergeOrder(baseIDs, mineIDs, theirIDs, keys(merged)) {
+		lanes = append(lanes, merged[id])
+	}
+	return lanes
+}
+
+func (m *merger) mergeStage(id string, holdsItems bool) *Stage {
+	base, inBase := m.base.stages[id]
+	mine, inMine := m.mine.stages[id]
+	theirs, inTheirs := m.theirs.stages[id]
+
+	switch {
+	case inMine && inTheirs:
+		if !inBase {
+			base = &Stage{}
+		}
+
+		merged := cloneStage(mine)
+		merged.Title = mergeField(m, id, "", mine.Title, "Title", base.Title, mine.Title, theirs.Title, inBase)
+		merged.WIPLimit = mergeField(m, id, "", mine.Title, "WIPLimit", base.WIPLimit, mine.WIPLimit, theirs.WIPLimit, inBase)
+		if theirs.Modified.After(merged.Modified) {
+			merged.Modified = theirs.Modified
+		}
+		return merged
+
+	case inMine && !inBase:
+		return cloneStage(mine)
+
+	case inTheirs && !inBase:
+		return cloneStage(theirs)
+
+	case inMine:
+		/* Removed by them, kept if changed by me or if it still holds changed items */
+		if holdsItems || !stageEqual(base, mine) {
+			m.conflict(id, "", mine.Title, "Removed", "changed", "removed")
+			return cloneStage(mine)
+		}
+
+	case inTheirs:
+		/* Removed by me, kept if changed by them or if it still holds changed items */
+		if holdsItems || !stageEqual(base, theirs) {
+			m.conflict(id, "", theirs.Title, "Removed", "removed", "changed")
+			return cloneStage(theirs)
+		}
+	}
+
+	return nil
+}
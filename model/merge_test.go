@@ -0,0 +1,162 @@
+package model
+
+/* This file contains the tests of the three-way merge of boards */
+
+/* ================================================================================ Imports */
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+/* ================================================================================ Public functions */
+func TestMerge(t *testing.T) {
+	rename := func(id, title string) func(b *Board) {
+		return func(b *Board) {
+			if item := b.ItemByID(id); item != nil {
+				item.Title = title
+			} else {
+				b.StageByID(id).Title = title
+			}
+		}
+	}
+	describe := func(id, description string) func(b *Board) {
+		return func(b *Board) { b.ItemByID(id).Description = description }
+	}
+	move := func(id, stageID string, index int) func(b *Board) {
+		return func(b *Board) { b.MoveItem(b.ItemByID(id), b.StageByID(stageID), index) }
+	}
+	remove := func(id string) func(b *Board) {
+		return func(b *Board) { b.RemoveItem(b.ItemByID(id)) }
+	}
+	add := func(id, stageID string) func(b *Board) {
+		return func(b *Board) {
+			if stage := b.StageByID(stageID); stage != nil {
+				stage.AppendItem(&Item{ID: id, Title: strings.ToUpper(id)})
+			} else {
+				b.AppendStage(&Stage{ID: stageID, Title: strings.ToUpper(stageID)})
+			}
+		}
+	}
+	unchanged := func(b *Board) {}
+
+	tests := []struct {
+		name      string
+		mine      func(b *Board)
+		theirs    func(b *Board)
+		want      string
+		conflicts []string
+	}{
+		{"unchanged", unchanged, unchanged, "Todo: A, B | Done: C", nil},
+		{"one side", rename("a", "A1"), unchanged, "Todo: A1, B | Done: C", nil},
+		{"other side", unchanged, rename("a", "A1"), "Todo: A1, B | Done: C", nil},
+		{"same change", rename("a", "A1"), rename("a", "A1"), "Todo: A1, B | Done: C", nil},
+		{"different fields", rename("a", "A1"), describe("a", "Text"), "Todo: A1, B | Done: C", nil},
+		{"edit and move", rename("a", "A1"), move("a", "done", 0), "Todo: B | Done: A1, C", nil},
+		{"reorder and edit", move("b", "todo", 0), rename("a", "A1"), "Todo: B, A1 | Done: C", nil},
+		{"conflicting titles", rename("a", "A1"), rename("a", "A2"), "Todo: A1, B | Done: C", []string{"Title"}},
+		{"move and reorder", move("a", "done", -1), move("a", "todo", -1), "Todo: B | Done: C, A", nil},
+		{"removed", remove("b"), unchanged, "Todo: A | Done: C", nil},
+		{"removed on both sides", remove("b"), remove("b"), "Todo: A | Done: C", nil},
+		{"removed and changed", remove("b"), describe("b", "Text"), "Todo: A, B | Done: C", []string{"Removed"}},
+		{"added on both sides", add("d", "todo"), add("e", "todo"), "Todo: A, B, E, D | Done: C", nil},
+		{"added stage", unchanged, add("doing", "doing"), "Todo: A, B | Done: C | DOING: ", nil},
+		{"conflicting stage titles", rename("todo", "Open"), rename("todo", "Backlog"), "Open: A, B | Done: C", []string{"Title"}},
+	}
+
+	for _, test := range tests {
+		base, mine, theirs := mergeTestBoard(), mergeTestBoard(), mergeTestBoard()
+		test.mine(mine)
+		test.theirs(theirs)
+
+		merged, conflicts := Merge(base, mine, theirs)
+
+		if got := testBoardLayout(merged); got != test.want {
+			t.Errorf("%s: merged board = %q, want %q", test.name, got, test.want)
+		}
+
+		fields := []string{}
+		for _, conflict := range conflicts {
+			fields = append(fields, conflict.Field)
+		}
+		if !slices.Equal(fields, test.conflicts) {
+			t.Errorf("%s: conflicts = %v, want %v", test.name, fields, test.conflicts)
+		}
+
+		if got := testBoardLayout(base); got != "Todo: A, B | Done: C" {
+			t.Errorf("%s: base board modified to %q", test.name, got)
+		}
+	}
+}
+
+func TestMergeWithoutBase(t *testing.T) {
+	mine, theirs := mergeTestBoard(), mergeTestBoard()
+	theirs.ItemByID("b").Title = "B1"
+
+	merged, conflicts := Merge(nil, mine, theirs)
+
+	if got := testBoardLayout(merged); got != "Todo: A, B | Done: C" {
+		t.Errorf("merged board = %q", got)
+	}
+	if len(conflicts) != 1 || conflicts[0].ItemID != "b" {
+		t.Errorf("conflicts = %v, want the title of b", conflicts)
+	}
+}
+
+func TestMergeCopiesItems(t *testing.T) {
+	recurrence, _ := ParseRecurrence("weekly:mon")
+	base, mine, theirs := mergeTestBoard(), mergeTestBoard(), mergeTestBoard()
+	item := theirs.ItemByID("a")
+	item.Checklist = []ChecklistEntry{{Text: "Step"}}
+	item.Recurrence = recurrence
+	added := &Item{ID: "d", Title: "D", Checklist: []ChecklistEntry{{Text: "Step"}}, Recurrence: recurrence.Clone()}
+	theirs.StageByID("todo").AppendItem(added)
+
+	merged, _ := Merge(base, mine, theirs)
+
+	/* Both the changed and the added item of theirs are copies */
+	for _, original := range []*Item{item, added} {
+		copied := merged.ItemByID(original.ID)
+		copied.Checklist[0].Done = true
+		copied.Recurrence.Days = append(copied.Recurrence.Days, 1)
+		copied.Recurrence.Weekdays[0] = time.Friday
+
+		if original.Checklist[0].Done || len(original.Recurrence.Days) > 0 || original.Recurrence.Weekdays[0] != time.Monday {
+			t.Errorf("editing merged item %s changed the item of theirs to %v, %v", original.ID, original.Checklist, original.Recurrence)
+		}
+	}
+}
+
+func TestMarkConflicts(t *testing.T) {
+	b := mergeTestBoard()
+	MarkConflicts(b, []Conflict{{ItemID: "a", Field: "Title", Mine: "A", Theirs: "A2"}, {ItemID: "b", Field: "Removed"}})
+
+	if a := b.ItemByID("a"); !a.HasTag(Tag{"Conflict=Title"}) || a.Description != "Title of theirs: A2" {
+		t.Errorf("item a has tags %v and description %q", a.Tags, a.Description)
+	}
+	if item := b.ItemByID("b"); !item.HasTag(Tag{"Conflict=Removed"}) || item.Description != "" {
+		t.Errorf("item b has tags %v and description %q", item.Tags, item.Description)
+	}
+}
+
+/* ================================================================================ Private functions */
+func mergeTestBoard() *Board {
+	return &Board{Name: "Board", Version: SCHEMA_VERSION, Stages: []*Stage{
+		{ID: "todo", Title: "Todo", Items: []*Item{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}}},
+		{ID: "done", Title: "Done", Items: []*Item{{ID: "c", Title: "C"}}},
+	}}
+}
+
+// testBoardLayout describes the stages and item titles of the board, e.g. "Todo: A, B | Done: C"
+func testBoardLayout(b *Board) string {
+	stages := []string{}
+	for _, stage := range b.Stages {
+		titles := []string{}
+		for _, item := range stage.Items {
+			titles = append(titles, item.Title)
+		}
+		stages = append(stages, stage.Title+": "+strings.Join(titles, ", "))
+	}
+	return strings.Join(stages, " | ")
+}
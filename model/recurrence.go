@@ -0,0 +1,248 @@
+package model
+
+/* Recurrence is the headless type describing the schedule of a recurring item: once the item is done, which means it
+   was moved to the last stage of the board, its next occurrence is created as a copy in the chosen stage on the day
+   the schedule gives - the recurrence passes on to the copy, so every occurrence is created only once */
+
+/* ================================================================================ Imports */
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* ================================================================================ Constants */
+const (
+	RECURRENCE_SEARCH_DAYS = 400 // a year of 13 lunar months, so every day of month of every calendar is found
+)
+
+/* ================================================================================ Public variables */
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+/* ================================================================================ Public types */
+type RecurrenceRule string
+
+const (
+	RecurDaily   RecurrenceRule = "daily"
+	RecurWeekly  RecurrenceRule = "weekly"
+	RecurMonthly RecurrenceRule = "monthly"
+	RecurLunar   RecurrenceRule = "lunar"
+	RecurTibetan RecurrenceRule = "tibetan"
+)
+
+type Recurrence struct {
+	Rule     RecurrenceRule
+	Weekdays []time.Weekday // days of the weekly rule
+	Days     []int          // days of month of the monthly, lunar and tibetan rules
+	StageID  string         // stage to create the next occurrence in, the first stage if unknown
+}
+
+// Calendars converts dates to the day of month in the lunar and Tibetan calendars, which are not part of the model
+type Calendars struct {
+	LunarDay   func(date time.Time) int
+	TibetanDay func(date time.Time) int
+}
+
+/* ================================================================================ Private variables */
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+/* ================================================================================ Public functions */
+// ParseRecurrence parses a recurrence like "daily", "weekly:mon,fri", "monthly:1,15", "lunar:1,15" or "tibetan:10,25",
+// the empty string is no recurrence
+func ParseRecurrence(text string) (*Recurrence, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil, nil
+	}
+
+	ruleText, daysText, _ := strings.Cut(text, ":")
+	recurrence := &Recurrence{Rule: RecurrenceRule(strings.TrimSpace(ruleText))}
+
+	days := []string{}
+	for _, day := range strings.Split(daysText, ",") {
+		if day = strings.TrimSpace(day); day != "" {
+			days = append(days, day)
+		}
+	}
+
+	switch recurrence.Rule {
+	case RecurDaily:
+		if len(days) > 0 {
+			return nil, fmt.Errorf("%w: %q takes no days", ErrInvalidRecurrence, text)
+		}
+
+	case RecurWeekly:
+		for _, day := range days {
+			weekday := slices.Index(weekdayNames, day[:min(len(day), 3)])
+			if weekday < 0 {
+				return nil, fmt.Errorf("%w: %q is no weekday", ErrInvalidRecurrence, day)
+			}
+			recurrence.Weekdays = append(recurrence.Weekdays, time.Weekday(weekday))
+		}
+		if len(recurrence.Weekdays) < 1 {
+			return nil, fmt.Errorf("%w: %q needs weekdays", ErrInvalidRecurrence, text)
+		}
+
+	case RecurMonthly, RecurLunar, RecurTibetan:
+		for _, day := range days {
+			number, err := strconv.Atoi(day)
+			if err != nil || number < 1 || number > 31 || (recurrence.Rule != RecurMonthly && number > 30) {
+				return nil, fmt.Errorf("%w: %q is no day of month", ErrInvalidRecurrence, day)
+			}
+			recurrence.Days = append(recurrence.Days, number)
+		}
+		if len(recurrence.Days) < 1 {
+			return nil, fmt.Errorf("%w: %q needs days of month", ErrInvalidRecurrence, text)
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: unknown rule %q", ErrInvalidRecurrence, ruleText)
+	}
+
+	return recurrence, nil
+}
+
+func RecurrenceEqual(a, b *Recurrence) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Rule == b.Rule && slices.Equal(a.Weekdays, b.Weekdays) && slices.Equal(a.Days, b.Days) && a.StageID == b.StageID
+}
+
+/* ================================================================================ Public methods */
+// String returns the recurrence in the format read by ParseRecurrence
+func (r *Recurrence) String() string {
+	if r == nil {
+		return ""
+	}
+
+	days := []string{}
+	for _, weekday := range r.Weekdays {
+		days = append(days, weekdayNames[weekday])
+	}
+	for _, day := range r.Days {
+		days = append(days, strconv.Itoa(day))
+	}
+
+	if len(days) < 1 {
+		return string(r.Rule)
+	}
+	return string(r.Rule) + ":" + strings.Join(days, ",")
+}
+
+func (r *Recurrence) Clone() *Recurrence {
+	if r == nil {
+		return nil
+	}
+	return &Recurrence{Rule: r.Rule, Weekdays: slices.Clone(r.Weekdays), Days: slices.Clone(r.Days), StageID: r.StageID}
+}
+
+// Matches returns whether the schedule includes the day
+func (r *Recurrence) Matches(date time.Time, calendars Calendars) bool {
+	switch r.Rule {
+	case RecurDaily:
+		return true
+	case RecurWeekly:
+		return slices.Contains(r.Weekdays, date.Weekday())
+	case RecurMonthly:
+		return slices.Contains(r.Days, date.Day())
+	case RecurLunar:
+		return calendars.LunarDay != nil && slices.Contains(r.Days, calendars.LunarDay(date))
+	case RecurTibetan:
+		return calendars.TibetanDay != nil && slices.Contains(r.Days, calendars.TibetanDay(date))
+	default:
+		return false
+	}
+}
+
+// Next returns the first day of the schedule after the given day, the zero time if there is none within a year
+func (r *Recurrence) Next(after time.Time, calendars Calendars) time.Time {
+	day := Date(after)
+	for range RECURRENCE_SEARCH_DAYS {
+		day = day.AddDate(0, 0, 1)
+		if r.Matches(day, calendars) {
+			return day
+		}
+	}
+	return time.Time{}
+}
+
+func (i *Item) SetRecurrence(recurrence *Recurrence) {
+	i.Recurrence = recurrence
+	i.Modified = time.Now()
+}
+
+// NextOccurrence returns the day of the next occurrence of the recurring item, which follows the day of the item (its
+// due date, or the day it was created) but is not before today, the zero time if the item does not recur
+func (i *Item) NextOccurrence(today time.Time, calendars Calendars) time.Time {
+	if i.Recurrence == nil {
+		return time.Time{}
+	}
+
+	day := i.Due
+	if day.IsZero() {
+		day = Date(i.Created)
+	}
+	if yesterday := Date(today).AddDate(0, 0, -1); day.Before(yesterday) {
+		day = yesterday
+	}
+	return i.Recurrence.Next(day, calendars)
+}
+
+// Occurrence returns a copy of the recurring item for the day, which is its due date, a start date is shifted along
+// and the checklist is open again
+func (i *Item) Occurrence(day time.Time) *Item {
+	occurrence := NewItem(i.Title, slices.Clone(i.Tags), i.Description, i.Style, i.DataType)
+	occurrence.LaneID = i.LaneID
+	occurrence.Recurrence = i.Recurrence.Clone()
+	for _, entry := range i.Checklist {
+		occurrence.Checklist = append(occurrence.Checklist, ChecklistEntry{Text: entry.Text})
+	}
+
+	start := time.Time{}
+	if !i.Start.IsZero() && !i.Due.IsZero() {
+		start = day.AddDate(0, 0, -int(math.Round(i.Due.Sub(i.Start).Hours()/24)))
+	}
+	occurrence.SetDates(start, day)
+
+	return occurrence
+}
+
+// RecurrenceStage returns the stage to create the next occurrence of the item in, nil if the board has no stages
+func (b *Board) RecurrenceStage(item *Item) *Stage {
+	if len(b.Stages) < 1 {
+		return nil
+	}
+
+	if item.Recurrence != nil {
+		if stage := b.StageByID(item.Recurrence.StageID); stage != nil {
+			return stage
+		}
+	}
+	return b.Stages[0]
+}
+
+// IsDone returns whether the item is in the last stage of the board
+func (b *Board) IsDone(item *Item) bool {
+	return len(b.Stages) > 0 && b.Stages[len(b.Stages)-1].ItemIndex(item) >= 0
+}
+
+// DueRecurrences returns the done recurring items, whose next occurrence is due until today
+func (b *Board) DueRecurrences(today time.Time, calendars Calendars) []*Item {
+	items := []*Item{}
+	if len(b.Stages) < 1 {
+		return items
+	}
+
+	for _, item := range b.Stages[len(b.Stages)-1].Items {
+		next := item.NextOccurrence(today, calendars)
+		if !next.IsZero() && !next.After(Date(today)) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,160 @@
+package model
+
+/* This file contains the tests of the recurrence schedules of recurring items */
+
+/* ================================================================================ Imports */
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+/* ================================================================================ Public functions */
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"  ", "", false},
+		{"daily", "daily", false},
+		{"Daily", "daily", false},
+		{"weekly:mon,fri", "weekly:mon,fri", false},
+		{"Weekly: Monday, Friday", "weekly:mon,fri", false},
+		{"weekly:sun,", "weekly:sun", false},
+		{"monthly:1,15", "monthly:1,15", false},
+		{"monthly:31", "monthly:31", false},
+		{"lunar:1,15", "lunar:1,15", false},
+		{"tibetan:10,25", "tibetan:10,25", false},
+		{"daily:1", "", true},
+		{"weekly", "", true},
+		{"weekly:xyz", "", true},
+		{"monthly", "", true},
+		{"monthly:0", "", true},
+		{"monthly:32", "", true},
+		{"monthly:first", "", true},
+		{"lunar:31", "", true},
+		{"tibetan:31", "", true},
+		{"yearly", "", true},
+	}
+
+	for _, test := range tests {
+		recurrence, err := ParseRecurrence(test.text)
+		if test.wantErr {
+			if !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("ParseRecurrence(%q) error = %v, want %v", test.text, err, ErrInvalidRecurrence)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) failed: %v", test.text, err)
+			continue
+		}
+		if got := recurrence.String(); got != test.want {
+			t.Errorf("ParseRecurrence(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	/* The test calendars count the days of month one day behind the Gregorian calendar */
+	calendars := Calendars{
+		LunarDay:   func(date time.Time) int { return date.AddDate(0, 0, -1).Day() },
+		TibetanDay: func(date time.Time) int { return date.AddDate(0, 0, -1).Day() },
+	}
+
+	tests := []struct {
+		recurrence string
+		after      time.Time
+		calendars  Calendars
+		want       time.Time
+	}{
+		{"daily", testDate(2025, 1, 31), calendars, testDate(2025, 2, 1)},
+		{"daily", time.Date(2025, 1, 31, 23, 59, 0, 0, time.Local), calendars, testDate(2025, 2, 1)},
+		{"weekly:mon,fri", testDate(2025, 1, 3), calendars, testDate(2025, 1, 6)},
+		{"weekly:mon,fri", testDate(2025, 1, 6), calendars, testDate(2025, 1, 10)},
+		{"monthly:1,15", testDate(2025, 1, 15), calendars, testDate(2025, 2, 1)},
+		{"monthly:31", testDate(2025, 1, 31), calendars, testDate(2025, 3, 31)},
+		{"monthly:29", testDate(2025, 1, 31), calendars, testDate(2025, 3, 29)},
+		{"lunar:5", testDate(2025, 1, 10), calendars, testDate(2025, 2, 6)},
+		{"tibetan:10", testDate(2025, 1, 1), calendars, testDate(2025, 1, 11)},
+		{"lunar:5", testDate(2025, 1, 10), Calendars{}, time.Time{}},
+	}
+
+	for _, test := range tests {
+		recurrence, _ := ParseRecurrence(test.recurrence)
+		if got := recurrence.Next(test.after, test.calendars); !got.Equal(test.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", test.recurrence, FormatDate(test.after), FormatDate(got), FormatDate(test.want))
+		}
+	}
+}
+
+func TestItemNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence string
+		created    time.Time
+		due        time.Time
+		today      time.Time
+		want       time.Time
+	}{
+		{"after due date", "daily", testDate(2025, 1, 1), testDate(2025, 1, 10), testDate(2025, 1, 5), testDate(2025, 1, 11)},
+		{"overdue", "daily", testDate(2025, 1, 1), testDate(2025, 1, 10), testDate(2025, 2, 1), testDate(2025, 2, 1)},
+		{"overdue weekly", "weekly:mon", testDate(2025, 1, 1), testDate(2025, 1, 6), testDate(2025, 1, 22), testDate(2025, 1, 27)},
+		{"after creation", "weekly:mon", time.Date(2025, 1, 20, 14, 30, 0, 0, time.Local), time.Time{}, testDate(2025, 1, 20), testDate(2025, 1, 27)},
+		{"no recurrence", "", testDate(2025, 1, 1), testDate(2025, 1, 10), testDate(2025, 1, 5), time.Time{}},
+	}
+
+	for _, test := range tests {
+		recurrence, _ := ParseRecurrence(test.recurrence)
+		item := &Item{Created: test.created, Due: test.due, Recurrence: recurrence}
+
+		if got := item.NextOccurrence(test.today, Calendars{}); !got.Equal(test.want) {
+			t.Errorf("%s: NextOccurrence(%s) = %s, want %s", test.name, FormatDate(test.today), FormatDate(got), FormatDate(test.want))
+		}
+	}
+}
+
+func TestItemOccurrence(t *testing.T) {
+	recurrence, _ := ParseRecurrence("weekly:mon")
+	item := &Item{Title: "Report", Start: testDate(2025, 1, 3), Due: testDate(2025, 1, 6), Recurrence: recurrence,
+		Checklist: []ChecklistEntry{{Text: "Write", Done: true}}}
+
+	occurrence := item.Occurrence(testDate(2025, 1, 13))
+
+	if !occurrence.Due.Equal(testDate(2025, 1, 13)) || !occurrence.Start.Equal(testDate(2025, 1, 10)) {
+		t.Errorf("Occurrence() dates = %s - %s, want 2025-01-10 - 2025-01-13", FormatDate(occurrence.Start), FormatDate(occurrence.Due))
+	}
+	if occurrence.ID == item.ID || occurrence.Recurrence == item.Recurrence || !RecurrenceEqual(occurrence.Recurrence, item.Recurrence) {
+		t.Errorf("Occurrence() is no independent copy")
+	}
+	if len(occurrence.Checklist) != 1 || occurrence.Checklist[0].Done {
+		t.Errorf("Occurrence() checklist = %v, want it open again", occurrence.Checklist)
+	}
+}
+
+func TestCreateOccurrencesWithoutStage(t *testing.T) {
+	recurrence, _ := ParseRecurrence("daily")
+	b := mergeTestBoard()
+	a, c := b.ItemByID("a"), b.ItemByID("c")
+	a.Recurrence, c.Recurrence = recurrence, recurrence.Clone()
+	c.Recurrence.StageID = "done"
+
+	command := &CreateOccurrencesCommand{Items: []*Item{a, c}, Today: testDate(2025, 1, 1)}
+	command.Do(b)
+	command.Undo(b)
+	b.RemoveStage(b.StageByID("done"))
+
+	if err := command.Do(b); !errors.Is(err, ErrStageNotFound) {
+		t.Errorf("Do() error = %v, want %v", err, ErrStageNotFound)
+	}
+	if got := testBoardLayout(b); got != "Todo: A, B" || a.Recurrence == nil || len(b.Activities) != 4 {
+		t.Errorf("failed Do() changed the board to %q with %d activities", got, len(b.Activities))
+	}
+}
+
+/* ================================================================================ Private functions */
+func testDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,144 @@
+package model
+
+/* This file contains the versioning of the saved board JSON and the migrations to upgrade files of older versions */
+
+/* ================================================================================ Imports */
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+/* ================================================================================ Constants */
+const (
+	SCHEMA_VERSION = 10
+)
+
+/* ================================================================================ Public variables */
+var ErrInvalidVersion = errors.New("invalid schema version")
+var ErrNotABoard = errors.New("not a board file, it has neither stages nor a schema version")
+
+/* ================================================================================ Public types */
+type UnsupportedVersionError struct {
+	Version int
+}
+
+/* ================================================================================ Private types */
+// migration upgrades a generic JSON document of a board by exactly one schema version
+type migration func(document map[string]any) error
+
+/* ================================================================================ Private variables */
+// migrations holds the migration from schema version i to i+1 at index i, files without version marker are version 0
+var migrations = []migration{
+	migrateDataType,
+	migrateIDs,
+	addOptionalFields, // 3: saved filter views
+	addOptionalFields, // 4: stage WIP limits
+	addOptionalFields, // 5: swimlanes and item lanes
+	addOptionalFields, // 6: item start and due dates
+	addOptionalFields, // 7: item recurrence
+	addOptionalFields, // 8: item checklists
+	addOptionalFields, // 9: plain text descriptions option
+	addOptionalFields, // 10: activity log
+}
+
+/* ================================================================================ Public functions */
+// Migrate upgrades the board JSON data to the current schema version, data of newer versions is refused
+func Migrate(data []byte) ([]byte, error) {
+	document := map[string]any{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if value, ok := document["Version"].(float64); ok {
+		version = int(value)
+	}
+
+	if version < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidVersion, version)
+	}
+	if version > SCHEMA_VERSION {
+		return nil, &UnsupportedVersionError{version}
+	}
+	if version == SCHEMA_VERSION {
+		return data, nil
+	}
+
+	for ; version < SCHEMA_VERSION; version++ {
+		if err := migrations[version](document); err != nil {
+			return nil, fmt.Errorf("migrating board from schema version %d: %w", version, err)
+		}
+	}
+	document["Version"] = SCHEMA_VERSION
+
+	return json.Marshal(document)
+}
+
+// CheckBoardData returns ErrNotABoard unless the data is a JSON object with stages or a schema version, which every
+// saved board has - Load accepts any JSON object and would treat other files as empty board
+func CheckBoardData(data []byte) error {
+	document := map[string]any{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	_, hasStages := document["Stages"]
+	_, hasVersion := document["Version"]
+	if !hasStages && !hasVersion {
+		return ErrNotABoard
+	}
+	return nil
+}
+
+/* ================================================================================ Public methods */
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("The board file was saved with schema version %d, but this version of BanKan only supports up to version %d.\n\nPlease update BanKan to open it.", e.Version, SCHEMA_VERSION)
+}
+
+/* ================================================================================ Private functions */
+func documentList(document map[string]any, key string) []map[string]any {
+	values, _ := document[key].([]any)
+	list := make([]map[string]any, 0, len(values))
+
+	for _, value := range values {
+		if entry, ok := value.(map[string]any); ok {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// migrateDataType sets the data type of items saved before it was introduced to "Normal"
+func migrateDataType(document map[string]any) error {
+	for _, stage := range documentList(document, "Stages") {
+		for _, item := range documentList(stage, "Items") {
+			if dataType, _ := item["DataType"].(string); dataType == "" {
+				item["DataType"] = "Normal"
+			}
+		}
+	}
+	return nil
+}
+
+// migrateIDs gives stages and items saved before identifiers were introduced a persistent ID
+func migrateIDs(document map[string]any) error {
+	for _, stage := range documentList(document, "Stages") {
+		if id, _ := stage["ID"].(string); id == "" {
+			stage["ID"] = NewID()
+		}
+
+		for _, item := range documentList(stage, "Items") {
+			if id, _ := item["ID"].(string); id == "" {
+				item["ID"] = NewID()
+			}
+		}
+	}
+	return nil
+}
+
+// addOptionalFields is the migration to versions that only added optional fields, which older files simply lack - the
+// version is raised anyway, so older versions of BanKan refuse the file instead of silently dropping the new fields
+func addOptionalFields(document map[string]any) error {
+	return nil
+}
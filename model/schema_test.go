@@ -0,0 +1,107 @@
+package model
+
+/* This file contains the tests of the schema versioning and the migrations of older board files */
+
+/* ================================================================================ Imports */
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+/* ================================================================================ Public functions */
+func TestMigrate(t *testing.T) {
+	current := fmt.Sprintf(`{"Version":%d,"Name":"Board","Stages":[]}`, SCHEMA_VERSION)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{"unversioned", `{"Name":"Board","Stages":[{"Title":"Todo","Items":[{"Title":"A"},{"Title":"B","DataType":"Lunar","ID":"b"}]}]}`, nil},
+		{"version 1", `{"Version":1,"Name":"Board","Stages":[{"Title":"Todo","Items":[{"Title":"A","DataType":"Normal"}]}]}`, nil},
+		{"previous version", fmt.Sprintf(`{"Version":%d,"Name":"Board","Stages":[]}`, SCHEMA_VERSION-1), nil},
+		{"current version", current, nil},
+		{"newer version", fmt.Sprintf(`{"Version":%d,"Name":"Board"}`, SCHEMA_VERSION+1), &UnsupportedVersionError{}},
+		{"negative version", `{"Version":-1,"Name":"Board"}`, ErrInvalidVersion},
+	}
+
+	for _, test := range tests {
+		data, err := Migrate([]byte(test.data))
+
+		unsupported := &UnsupportedVersionError{}
+		switch {
+		case test.wantErr == nil && err != nil:
+			t.Errorf("%s: Migrate() failed: %v", test.name, err)
+			continue
+		case test.wantErr == ErrInvalidVersion && !errors.Is(err, ErrInvalidVersion):
+			t.Errorf("%s: Migrate() error = %v, want %v", test.name, err, ErrInvalidVersion)
+			continue
+		case test.wantErr != nil && test.wantErr != ErrInvalidVersion && !errors.As(err, &unsupported):
+			t.Errorf("%s: Migrate() error = %v, want an unsupported version", test.name, err)
+			continue
+		case test.wantErr != nil:
+			continue
+		}
+
+		b := &Board{}
+		if err := json.Unmarshal(data, b); err != nil {
+			t.Errorf("%s: migrated data is invalid: %v", test.name, err)
+			continue
+		}
+		if b.Version != SCHEMA_VERSION {
+			t.Errorf("%s: migrated version = %d, want %d", test.name, b.Version, SCHEMA_VERSION)
+		}
+		for _, stage := range b.Stages {
+			if stage.ID == "" {
+				t.Errorf("%s: stage %q has no ID", test.name, stage.Title)
+			}
+			for _, item := range stage.Items {
+				if item.ID == "" || item.DataType == "" {
+					t.Errorf("%s: item %q has ID %q and data type %q", test.name, item.Title, item.ID, item.DataType)
+				}
+			}
+		}
+	}
+
+	/* Existing values are kept */
+	data, _ := Migrate([]byte(tests[0].data))
+	b := &Board{}
+	json.Unmarshal(data, b)
+	if item := b.Stages[0].Items[1]; item.ID != "b" || item.DataType != "Lunar" {
+		t.Errorf("migration changed item to ID %q and data type %q", item.ID, item.DataType)
+	}
+
+	/* Data of the current version is not touched */
+	if data, _ := Migrate([]byte(current)); string(data) != current {
+		t.Errorf("Migrate() of the current version = %s", data)
+	}
+}
+
+func TestMigrationsCoverSchemaVersion(t *testing.T) {
+	if len(migrations) != SCHEMA_VERSION {
+		t.Errorf("%d migrations for schema version %d", len(migrations), SCHEMA_VERSION)
+	}
+}
+
+func TestCheckBoardData(t *testing.T) {
+	tests := []struct {
+		data    string
+		wantErr bool
+	}{
+		{`{"Stages":[]}`, false},
+		{`{"Version":2}`, false},
+		{`{"Name":"Board","Stages":null}`, false},
+		{`{}`, true},
+		{`{"lists":[],"cards":[]}`, true},
+		{`[]`, true},
+		{`no json`, true},
+	}
+
+	for _, test := range tests {
+		if err := CheckBoardData([]byte(test.data)); (err != nil) != test.wantErr {
+			t.Errorf("CheckBoardData(%s) = %v, want error %v", test.data, err, test.wantErr)
+		}
+	}
+}
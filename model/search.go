@@ -0,0 +1,91 @@
+package model
+
+/* This file contains the full-text search in the titles, descriptions and tags of items */
+
+/* ================================================================================ Imports */
+import (
+	"regexp"
+)
+
+/* ================================================================================ Public types */
+type SearchOptions struct {
+	MatchCase bool
+	WholeWord bool
+	Regex     bool
+}
+
+// Search is a compiled search query, the empty search matches nothing
+type Search struct {
+	Query   string
+	Options SearchOptions
+	pattern *regexp.Regexp
+}
+
+/* ================================================================================ Public functions */
+// NewSearch compiles the query, which is taken literally unless the regex option is set
+func NewSearch(query string, options SearchOptions) (*Search, error) {
+	search := &Search{Query: query, Options: options}
+	if query == "" {
+		return search, nil
+	}
+
+	expression := query
+	if !options.Regex {
+		expression = regexp.QuoteMeta(query)
+	}
+	if options.WholeWord {
+		expression = `\b(?:` + expression + `)\b`
+	}
+	if !options.MatchCase {
+		expression = `(?i)` + expression
+	}
+
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	search.pattern = pattern
+
+	return search, nil
+}
+
+/* ================================================================================ Public methods */
+func (s *Search) IsEmpty() bool {
+	return s == nil || s.pattern == nil
+}
+
+// FindAll returns the start and end byte offsets of all non-empty matches in the text
+func (s *Search) FindAll(text string) [][]int {
+	if s.IsEmpty() {
+		return nil
+	}
+
+	matches := [][]int{}
+	for _, match := range s.pattern.FindAllStringIndex(text, -1) {
+		if match[1] > match[0] {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// Matches reports whether the title, the description, one of the checklist entries or one of the tags of the item
+// contains a match
+func (s *Search) Matches(item *Item) bool {
+	if len(s.FindAll(item.Title)) > 0 || len(s.FindAll(item.Description)) > 0 {
+		return true
+	}
+
+	for _, entry := range item.Checklist {
+		if len(s.FindAll(entry.Text)) > 0 {
+			return true
+		}
+	}
+
+	for _, tag := range item.Tags {
+		if len(s.FindAll(tag.Expression)) > 0 || len(s.FindAll(tag.DisplayString())) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package model
+
+/* Stage is the headless type describing a column/category of a board, which contains and orders items */
+
+/* ================================================================================ Imports */
+import (
+	"time"
+)
+
+/* ================================================================================ Public types */
+type Stage struct {
+	ID       string
+	Created  time.Time
+	Modified time.Time
+	Title    string
+	WIPLimit int // maximum number of items the stage should hold, 0 means no limit
+	Items    []*Item
+}
+
+/* ================================================================================ Public functions */
+func NewStage(title string) *Stage {
+	now := time.Now()
+	return &Stage{ID: NewID(), Created: now, Modified: now, Title: title}
+}
+
+/* ================================================================================ Public methods */
+func (s *Stage) Rename(title string) {
+	s.Title = title
+	s.Modified = time.Now()
+}
+
+// SetWIPLimit sets the maximum number of items, a limit of 0 or less removes it
+func (s *Stage) SetWIPLimit(limit int) {
+	s.WIPLimit = max(limit, 0)
+	s.Modified = time.Now()
+}
+
+// ExceedsWIPLimit reports whether the stage would hold more items than its WIP limit after adding the given number
+func (s *Stage) ExceedsWIPLimit(added int) bool {
+	return s.WIPLimit > 0 && len(s.Items)+added > s.WIPLimit
+}
+
+func (s *Stage) ItemByID(id string) *Item {
+	for _, item := range s.Items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+func (s *Stage) ItemIndex(toFind *Item) int {
+	for i, item := range s.Items {
+		if item == toFind {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Stage) AppendItem(item *Item) {
+	s.Items = append(s.Items, item)
+}
+
+// InsertItem inserts the item before the given index, an index out of range appends it
+func (s *Stage) InsertItem(index int, item *Item) {
+	if index < 0 || index >= len(s.Items) {
+		s.AppendItem(item)
+		return
+	}
+
+	s.Items = append(s.Items, nil)
+	copy(s.Items[index+1:], s.Items[index:])
+	s.Items[index] = item
+}
+
+func (s *Stage) RemoveItem(toRemove *Item) bool {
+	i := s.ItemIndex(toRemove)
+	if i < 0 {
+		return false
+	}
+
+	s.Items = append(s.Items[:i], s.Items[i+1:]...)
+
+	return true
+}
+
+func (s *Stage) FilterItems(filter *Filter) []*Item {
+	items := []*Item{}
+
+	for _, item := range s.Items {
+		if filter.Matches(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
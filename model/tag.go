@@ -1,4 +1,4 @@
-package main
+package model
 
 /* Tag is a basic type describing a tag to categorize items, either as simple statement or as an expression */
 
@@ -65,4 +65,4 @@ func (t *Tag) DisplayString() string {
 	} else {
 		return before
 	}
-}
\ No newline at end of file
+}
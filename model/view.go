@@ -0,0 +1,56 @@
+package model
+
+/* FilterView is the headless type describing a named filter query saved with the board, to switch between often
+   used filters quickly */
+
+/* ================================================================================ Public types */
+type FilterView struct {
+	Name  string
+	Query string
+}
+
+/* ================================================================================ Public methods */
+func (b *Board) ViewIndex(name string) int {
+	for i, view := range b.Views {
+		if view.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *Board) ViewByName(name string) *FilterView {
+	if i := b.ViewIndex(name); i >= 0 {
+		return &b.Views[i]
+	}
+	return nil
+}
+
+// ViewByQuery returns the first view with the given query, e.g. to find the view matching the current filter
+func (b *Board) ViewByQuery(query string) *FilterView {
+	for i, view := range b.Views {
+		if view.Query == query {
+			return &b.Views[i]
+		}
+	}
+	return nil
+}
+
+// SetView replaces the query of the view with the same name or appends the view
+func (b *Board) SetView(view FilterView) {
+	if i := b.ViewIndex(view.Name); i >= 0 {
+		b.Views[i] = view
+	} else {
+		b.Views = append(b.Views, view)
+	}
+}
+
+func (b *Board) RemoveView(name string) bool {
+	i := b.ViewIndex(name)
+	if i < 0 {
+		return false
+	}
+
+	b.Views = append(b.Views[:i], b.Views[i+1:]...)
+	return true
+}
@@ -0,0 +1,128 @@
+package main
+
+/* ProgressBadge is a widget type rendering a compact "done/total" counter followed by a small progress bar, sized
+   like the tag labels of an item to fit into their flow */
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/widget"
+)
+
+/* ================================================================================ Constants */
+const (
+	PROGRESS_BAR_WIDTH_FACTOR = 3.0 // width of the bar relative to the text height
+)
+
+/* ================================================================================ Public types */
+type ProgressBadge struct {
+	widget.BaseWidget
+	Done, Total                      int
+	Style                            PaintStyle
+	TextSize                         float32
+	BackgroundPaddings, TextPaddings Paddings
+}
+
+/* ================================================================================ Private types */
+type progressBadgeRenderer struct {
+	background *canvas.Rectangle
+	text       *canvas.Text
+	track      *canvas.Rectangle
+	bar        *canvas.Rectangle
+	w          *ProgressBadge
+}
+
+/* ================================================================================ Public functions */
+func NewProgressBadge(done, total int, style PaintStyle, textSize float32, paddingMultipliers, textPaddingOffsets Paddings) *ProgressBadge {
+	backgroundPaddings, textPaddings := CalculatePaddings(paddingMultipliers, textPaddingOffsets)
+
+	w := &ProgressBadge{Done: done, Total: total, Style: style, TextSize: textSize, BackgroundPaddings: backgroundPaddings, TextPaddings: textPaddings}
+	w.ExtendBaseWidget(w)
+
+	return w
+}
+
+/* ================================================================================ Private methods */
+func (w *ProgressBadge) text() string {
+	return fmt.Sprintf("%d/%d", w.Done, w.Total)
+}
+
+/* ================================================================================ Public rendering methods */
+func (w *ProgressBadge) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+
+	text := canvas.NewText(w.text(), w.Style.Foreground)
+	text.TextSize = w.TextSize
+	text.TextStyle = fyne.TextStyle{Bold: true}
+
+	r := &progressBadgeRenderer{canvas.NewRectangle(w.Style.Background), text, canvas.NewRectangle(color.Transparent), canvas.NewRectangle(color.Transparent), w}
+	r.Refresh()
+
+	return r
+}
+
+func (r progressBadgeRenderer) barWidth() float32 {
+	return r.text.MinSize().Height * PROGRESS_BAR_WIDTH_FACTOR
+}
+
+func (r progressBadgeRenderer) Layout(size fyne.Size) {
+	r.background.Resize(fyne.NewSize(size.Width-r.w.BackgroundPaddings.Left-r.w.BackgroundPaddings.Right, size.Height-r.w.BackgroundPaddings.Top-r.w.BackgroundPaddings.Bottom))
+	r.background.Move(fyne.NewPos(r.w.BackgroundPaddings.Left, r.w.BackgroundPaddings.Top))
+
+	textSize := r.text.MinSize()
+	r.text.Resize(textSize)
+	r.text.Move(fyne.NewPos(r.w.TextPaddings.Left, r.w.TextPaddings.Top))
+
+	barHeight := textSize.Height / 3
+	barPosition := fyne.NewPos(r.w.TextPaddings.Left+textSize.Width+r.w.TextPaddings.Right, r.w.TextPaddings.Top+(textSize.Height-barHeight)/2)
+
+	r.track.Resize(fyne.NewSize(r.barWidth(), barHeight))
+	r.track.Move(barPosition)
+
+	ratio := float32(0)
+	if r.w.Total > 0 {
+		ratio = float32(r.w.Done) / float32(r.w.Total)
+	}
+	r.bar.Resize(fyne.NewSize(r.barWidth()*ratio, barHeight))
+	r.bar.Move(barPosition)
+}
+
+func (r progressBadgeRenderer) MinSize() fyne.Size {
+	textSize := r.text.MinSize()
+
+	return fyne.NewSize(r.w.TextPaddings.Left+textSize.Width+r.w.TextPaddings.Right+r.barWidth()+r.w.TextPaddings.Right, r.w.TextPaddings.Top+textSize.Height+r.w.TextPaddings.Bottom)
+}
+
+func (r progressBadgeRenderer) Refresh() {
+	r.background.FillColor = r.w.Style.Background
+	r.background.Refresh()
+
+	r.text.Text = r.w.text()
+	r.text.Color = r.w.Style.Foreground
+	r.text.TextSize = r.w.TextSize
+	r.text.Refresh()
+
+	/* The track is a faint version of the text color, the bar turns green once everything is done */
+	foreground := r.w.Style.Foreground
+	r.track.FillColor = color.RGBA{foreground.R, foreground.G, foreground.B, 96}
+	r.track.Refresh()
+
+	r.bar.FillColor = foreground
+	if r.w.Total > 0 && r.w.Done == r.w.Total {
+		r.bar.FillColor = color.RGBA{76, 175, 80, 255}
+	}
+	r.bar.Refresh()
+
+	r.Layout(r.w.Size())
+}
+
+func (r progressBadgeRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.background, r.text, r.track, r.bar}
+}
+
+func (r progressBadgeRenderer) Destroy() {
+}
@@ -0,0 +1,186 @@
+package main
+
+/* This file contains the full-text search bar, which highlights matches in the items and navigates between them */
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+/* ================================================================================ Private variables */
+var searchBar *fyne.Container
+var searchEntry *widget.Entry
+var matchCaseCheck *widget.Check
+var wholeWordCheck *widget.Check
+var regexCheck *widget.Check
+var searchStatusLabel *widget.Label
+var searchHits []*model.Item
+var searchHitIndex int
+var searchExpandedItem *model.Item // collapsed item expanded to show the current hit, collapsed again when the search moves on
+
+/* ================================================================================ Private functions */
+func newSearchBar() *fyne.Container {
+	searchEntry = widget.NewEntry()
+	searchEntry.SetPlaceHolder("Search in titles, descriptions and tags ...")
+	searchEntry.OnChanged = func(string) { updateSearch() }
+	searchEntry.OnSubmitted = func(string) { showSearchHit(1) }
+
+	matchCaseCheck = widget.NewCheck("Match Case", func(bool) { updateSearch() })
+	wholeWordCheck = widget.NewCheck("Whole Word", func(bool) { updateSearch() })
+	regexCheck = widget.NewCheck("Regex", func(bool) { updateSearch() })
+	searchStatusLabel = widget.NewLabel("")
+
+	searchToolbar := widget.NewToolbar(
+		widget.NewToolbarAction(theme.MoveUpIcon(), func() { showSearchHit(-1) }),
+		widget.NewToolbarAction(theme.MoveDownIcon(), func() { showSearchHit(1) }),
+		widget.NewToolbarAction(theme.CancelIcon(), hideSearchBar),
+	)
+	options := container.NewHBox(matchCaseCheck, wholeWordCheck, regexCheck, searchStatusLabel, searchToolbar)
+
+	searchBar = container.NewBorder(nil, nil, nil, options, searchEntry)
+	searchBar.Hide()
+
+	return searchBar
+}
+
+func showSearchBar() {
+	searchBar.Show()
+	window.Canvas().Focus(searchEntry)
+	updateSearch()
+}
+
+func hideSearchBar() {
+	searchBar.Hide()
+	searchHits = nil
+	collapseSearchExpandedItem()
+	board.SetSearch(nil, nil)
+}
+
+func toggleSearchBar() {
+	if searchBar.Visible() {
+		hideSearchBar()
+	} else {
+		showSearchBar()
+	}
+}
+
+func searchShortcut(shortcut fyne.Shortcut) {
+	showSearchBar()
+}
+
+// updateSearch compiles the search from the entry and the options, an invalid regular expression is shown in the bar
+func updateSearch() {
+	options := model.SearchOptions{MatchCase: matchCaseCheck.Checked, WholeWord: wholeWordCheck.Checked, Regex: regexCheck.Checked}
+
+	search, err := model.NewSearch(searchEntry.Text, options)
+	if err != nil {
+		searchHits = nil
+		board.SetSearch(nil, nil)
+		searchStatusLabel.SetText("Invalid expression")
+		return
+	}
+
+	board.Search = search
+	searchHits = nil
+	syncSearchHits()
+	if search.IsEmpty() {
+		board.SetSearch(search, nil)
+	}
+	if len(searchHits) > 0 {
+		revealSearchHit(searchHits[0])
+	} else {
+		collapseSearchExpandedItem()
+	}
+}
+
+// syncSearchHits updates the hits after changes of the board, the current hit is kept if it still matches
+func syncSearchHits() {
+	if searchBar == nil || !searchBar.Visible() || board.Search.IsEmpty() {
+		searchHits = nil
+		if searchStatusLabel != nil {
+			searchStatusLabel.SetText("")
+		}
+		if board.SearchHit != nil {
+			board.SetSearch(board.Search, nil)
+		}
+		return
+	}
+
+	var current *model.Item
+	if searchHitIndex < len(searchHits) {
+		current = searchHits[searchHitIndex]
+	}
+
+	/* Items hidden by the tag filter cannot be shown, so they are no hits */
+	searchHits = []*model.Item{}
+	for _, item := range board.SearchItems(board.Search) {
+		if board.Filter.Matches(item) {
+			searchHits = append(searchHits, item)
+		}
+	}
+
+	searchHitIndex = 0
+	for i, hit := range searchHits {
+		if hit == current {
+			searchHitIndex = i
+		}
+	}
+
+	var hit *model.Item
+	if len(searchHits) > 0 {
+		hit = searchHits[searchHitIndex]
+		searchStatusLabel.SetText(fmt.Sprintf("%d of %d", searchHitIndex+1, len(searchHits)))
+	} else {
+		searchStatusLabel.SetText("No matches")
+	}
+
+	if hit != board.SearchHit || current == nil {
+		board.SetSearch(board.Search, hit)
+	}
+}
+
+// showSearchHit moves the current hit forward or backward, wrapping around at the ends, and scrolls to it
+func showSearchHit(step int) {
+	if len(searchHits) < 1 {
+		return
+	}
+
+	searchHitIndex = (searchHitIndex + step + len(searchHits)) % len(searchHits)
+	hit := searchHits[searchHitIndex]
+
+	searchStatusLabel.SetText(fmt.Sprintf("%d of %d", searchHitIndex+1, len(searchHits)))
+	board.SetSearch(board.Search, hit)
+	revealSearchHit(hit)
+}
+
+// revealSearchHit scrolls to the hit, a collapsed hit is expanded if the match is in its hidden description
+func revealSearchHit(hit *model.Item) {
+	if hit != searchExpandedItem {
+		collapseSearchExpandedItem()
+	}
+
+	if !hit.Expanded && len(board.Search.FindAll(hit.Description)) > 0 {
+		hit.Expanded = true
+		searchExpandedItem = hit
+		board.Refresh()
+	}
+
+	board.ShowItem(hit)
+}
+
+func collapseSearchExpandedItem() {
+	if searchExpandedItem == nil {
+		return
+	}
+
+	searchExpandedItem.Expanded = false
+	searchExpandedItem = nil
+	board.Refresh()
+}
@@ -0,0 +1,185 @@
+package main
+
+/* This file contains the snapshot of a board as PNG image or PDF document, rendered by the software painter of Fyne
+   without window - every stage is as high as all of its items, so nothing is cut off by scrolling */
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/theme"
+)
+
+/* ================================================================================ Constants */
+const (
+	SNAPSHOT_DEFAULT_WIDTH = 1600
+	SNAPSHOT_MIN_WIDTH     = 200
+	SNAPSHOT_PDF_DPI       = 96 // pixels per inch of the PDF page, which is measured in points (1/72 inch)
+)
+
+/* ================================================================================ Public variables */
+var ErrUnknownSnapshotFormat = errors.New("unknown snapshot format")
+
+/* ================================================================================ Public types */
+type SnapshotFormat string
+
+const (
+	SnapshotPNG SnapshotFormat = "png"
+	SnapshotPDF SnapshotFormat = "pdf"
+)
+
+/* ================================================================================ Private types */
+// snapshotTheme is the default theme in its dark variant, which the board colors are made for
+type snapshotTheme struct {
+	fyne.Theme
+}
+
+/* ================================================================================ Public functions */
+// ParseSnapshotFormat returns the format of the name or file extension (e.g. ".pdf")
+func ParseSnapshotFormat(name string) (SnapshotFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "png":
+		return SnapshotPNG, nil
+	case "pdf":
+		return SnapshotPDF, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownSnapshotFormat, name)
+	}
+}
+
+// SnapshotFormatOfPath returns the format of the file extension, PNG if unknown
+func SnapshotFormatOfPath(path string) SnapshotFormat {
+	if format, err := ParseSnapshotFormat(filepath.Ext(path)); err == nil {
+		return format
+	}
+	return SnapshotPNG
+}
+
+// RenderBoardSnapshot renders the board model at the given width with the stages side by side, the height follows
+// from the stage with the most items
+func RenderBoardSnapshot(b *model.Board, width float32) image.Image {
+	width = max(width, SNAPSHOT_MIN_WIDTH)
+
+	snapshot := NewBoard(b.Name, nil)
+	snapshot.Board = b
+
+	title := NewCustomLabel(fyne.TextAlignCenter, PaintStyle{color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, false, b.Name, GetScaledTextSubHeadingSize(), fyne.TextStyle{}, Paddings{1.0, 1.0, 1.0, 1.0}, Paddings{0.0, 0.0, 0.0, 0.0})
+
+	/* The theme is overridden instead of set, as there may be no app (on the command line) or one with another theme */
+	darkTheme := snapshotTheme{theme.DefaultTheme()}
+	background := canvas.NewRectangle(darkTheme.Color(theme.ColorNameBackground, theme.VariantDark))
+	content := container.NewStack(background, container.NewThemeOverride(container.NewPadded(container.NewBorder(title, nil, nil, nil, snapshot)), darkTheme))
+
+	offscreen := software.NewCanvas()
+	offscreen.SetPadded(false)
+	offscreen.SetContent(content)
+	offscreen.Resize(fyne.NewSize(width, content.MinSize().Height))
+
+	/* The item heights depend on the wrapping at the stage width, so the stages are measured once laid out, twice as
+	   the scroll bars of the first layout take some width */
+	for range 2 {
+		stagesHeight := float32(0)
+		for _, stage := range snapshot.StageWidgets() {
+			if stage.scrollArea == nil {
+				continue
+			}
+			headerHeight := stage.Size().Height - stage.scrollArea.Size().Height
+			stagesHeight = max(stagesHeight, headerHeight+stage.scrollArea.Content.MinSize().Height)
+		}
+		offscreen.Resize(fyne.NewSize(width, title.MinSize().Height+theme.Padding()*3+stagesHeight))
+	}
+
+	return offscreen.Capture()
+}
+
+// WriteBoardSnapshot renders the board and writes it in the format
+func WriteBoardSnapshot(writer io.Writer, b *model.Board, width float32, format SnapshotFormat) error {
+	img := RenderBoardSnapshot(b, width)
+
+	switch format {
+	case SnapshotPNG:
+		return png.Encode(writer, img)
+	case SnapshotPDF:
+		return writeImagePDF(writer, img)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownSnapshotFormat, format)
+	}
+}
+
+/* ================================================================================ Public methods */
+func (f SnapshotFormat) Extension() string {
+	return "." + string(f)
+}
+
+func (t snapshotTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	return t.Theme.Color(name, theme.VariantDark)
+}
+
+/* ================================================================================ Private functions */
+// writeImagePDF writes a single page PDF document showing the image, which is embedded losslessly
+func writeImagePDF(writer io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			pixels = append(pixels, pixel.R, pixel.G, pixel.B)
+		}
+	}
+
+	compressed := &bytes.Buffer{}
+	compressor := zlib.NewWriter(compressed)
+	if _, err := compressor.Write(pixels); err != nil {
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+
+	pageWidth := float64(bounds.Dx()) * 72 / SNAPSHOT_PDF_DPI
+	pageHeight := float64(bounds.Dy()) * 72 / SNAPSHOT_PDF_DPI
+	drawing := fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Im0 Do Q", pageWidth, pageHeight)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /XObject << /Im0 4 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", bounds.Dx(), bounds.Dy(), compressed.Len(), compressed.Bytes()),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(drawing), drawing),
+	}
+
+	/* The cross-reference table holds the byte offset of every object */
+	document := &bytes.Buffer{}
+	document.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = document.Len()
+		fmt.Fprintf(document, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := document.Len()
+	fmt.Fprintf(document, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(document, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(document, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := writer.Write(document.Bytes())
+	return err
+}
@@ -1,22 +1,33 @@
 package main
 
-/* Stage is a widget type describing a column/category of a board, which contains and manages items */
+/* Stage is a widget type rendering a column/category of a board model, which contains and manages item widgets */
 
 /* ================================================================================ Imports */
 import (
+	"fmt"
 	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"bankan/model"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
 /* ================================================================================ Public types */
 type Stage struct {
-	widget.BaseWidget `json:"-"`
-	Title             string
-	Items             []*Item
+	widget.BaseWidget
+	*model.Stage
+	board      *Board
+	items      []*Item
+	lanes      []*LaneSection
+	scrollArea *container.Scroll
 }
 
 /* ================================================================================ Private types */
@@ -31,107 +42,141 @@ type stageRenderer struct {
 }
 
 /* ================================================================================ Public functions */
-func NewStage(title string) *Stage {
-	stage := &Stage{Title: title}
-	stage.ExtendBaseWidget(stage)
+func NewStage(board *Board, stage *model.Stage) *Stage {
+	w := &Stage{Stage: stage, board: board}
+	w.ExtendBaseWidget(w)
 
-	return stage
+	return w
 }
 
-/* ================================================================================ Public methods */
-func (w *Stage) ItemIndex(toFind *Item) int {
-	for i, item := range w.Items {
-		if item == toFind {
-			return i
+/* ================================================================================ Private methods */
+func (w *Stage) itemWidget(item *model.Item) *Item {
+	for _, existing := range w.items {
+		if existing.Item == item {
+			return existing
 		}
 	}
-	return -1
+	return NewItem(w.board, item)
 }
 
-func (w *Stage) ItemAtPosition(position fyne.Position) *Item {
-	for _, item := range w.Items {
-		itemRect := Rectangle{item.Position(), item.Size()}
+// syncItems updates the item widgets to match the items of the stage model, keeping existing widgets
+func (w *Stage) syncItems() {
+	items := make([]*Item, len(w.Stage.Items))
+
+	for i, item := range w.Stage.Items {
+		items[i] = w.itemWidget(item)
+	}
+	w.items = items
+}
 
-		if itemRect.Contains(position) {
-			return item
+func (w *Stage) laneSection(lane *model.Lane) *LaneSection {
+	for _, existing := range w.lanes {
+		if existing.Lane == lane {
+			return existing
 		}
 	}
 	return nil
 }
 
-func (w *Stage) AppendItem(title string, tags []Tag, description string, style ItemStyle) {
-	item := NewItem(title, tags, description, style, "Normal")
+// scrolled keeps the scroll offsets of all stages equal while the board has lanes, so the lanes stay lined up
+func (w *Stage) scrolled(offset fyne.Position) {
+	if len(w.board.Lanes) < 1 {
+		return
+	}
 
-	w.Items = append(w.Items, item)
-	w.Refresh()
-	autoSave()
+	for _, stage := range w.board.StageWidgets() {
+		if stage != w && stage.scrollArea != nil {
+			stage.scrollArea.ScrollToOffset(offset)
+		}
+	}
 }
 
-func (w *Stage) AppendItemWithDataType(title string, tags []Tag, description string, style ItemStyle, dataType string) {
-	item := NewItem(title, tags, description, style, dataType)
+func (w *Stage) relayoutLanes() {
+	if w.scrollArea != nil {
+		w.scrollArea.Content.Refresh()
+		w.scrollArea.Refresh()
+	}
+}
 
-	w.Items = append(w.Items, item)
-	w.Refresh()
-	autoSave()
+func (w *Stage) scrollTo(offset fyne.Position) {
+	w.scrollArea.ScrollToOffset(offset)
+	w.scrolled(offset)
 }
 
-func (w *Stage) InsertItem(after bool, reference *Item, title string, tags []Tag, description string, style ItemStyle) bool {
-	i := w.ItemIndex(reference)
-	if i < 0 {
-		return false
+// titleText returns the title shown in the header, followed by the item count and the WIP limit if the stage has one
+func (w *Stage) titleText() string {
+	if w.WIPLimit > 0 {
+		return fmt.Sprintf("%s  %d/%d", w.Title, len(w.Items), w.WIPLimit)
 	}
-	if after {
-		i++
+	return w.Title
+}
+
+func (w *Stage) titleColor() color.RGBA {
+	if w.ExceedsWIPLimit(0) {
+		return ColorToRGBA(theme.Color(theme.ColorNameError))
 	}
+	return color.RGBA{255, 255, 255, 255}
+}
 
-	w.Items = append(w.Items, nil)
-	copy(w.Items[i+1:], w.Items[i:])
+/* ================================================================================ Public methods */
+func (w *Stage) ItemWidgets() []*Item {
+	w.syncItems()
+	return w.items
+}
 
-	item := NewItem(title, tags, description, style, "Normal")
-	w.Items[i] = item
-	w.Refresh()
-	autoSave()
+// LaneSections returns the sections of the stage for the lanes of the board, holding the item widgets of their lane
+func (w *Stage) LaneSections() []*LaneSection {
+	items := w.ItemWidgets()
+	sections := make([]*LaneSection, len(w.board.Lanes))
 
-	return true
-}
+	for i, lane := range w.board.Lanes {
+		section := w.laneSection(lane)
+		if section == nil {
+			section = NewLaneSection(lane, w)
+		}
 
-func (w *Stage) InsertItemWithDataType(after bool, reference *Item, title string, tags []Tag, description string, style ItemStyle, dataType string) bool {
-	i := w.ItemIndex(reference)
-	if i < 0 {
-		return false
-	}
-	if after {
-		i++
+		section.items = []*Item{}
+		for _, item := range items {
+			if w.board.ItemLane(item.Item) == lane {
+				section.items = append(section.items, item)
+			}
+		}
+		sections[i] = section
 	}
+	w.lanes = sections
 
-	w.Items = append(w.Items, nil)
-	copy(w.Items[i+1:], w.Items[i:])
-
-	item := NewItem(title, tags, description, style, dataType)
-	w.Items[i] = item
-	w.Refresh()
-	autoSave()
+	return sections
+}
 
-	return true
+func (w *Stage) AppendItem(item *model.Item) {
+	w.board.Execute(&model.AddItemCommand{Stage: w.Stage, Item: item, Index: -1})
 }
 
 func (w *Stage) RemoveItem(toRemove *Item) bool {
-	i := w.ItemIndex(toRemove)
-	if i < 0 {
+	if w.ItemIndex(toRemove.Item) < 0 {
 		return false
 	}
 
-	w.Items = append(w.Items[:i], w.Items[i+1:]...)
-	w.Refresh()
-	autoSave()
-
-	return true
+	return w.board.Execute(&model.RemoveItemCommand{Item: toRemove.Item})
 }
 
 func (w *Stage) ShowCreateItemDialog() {
-	ShowItemDialogWithDataType("New", "", "", "", ItemStyle{color.RGBA{0, 0, 0, 255}, color.RGBA{192, 192, 192, 255}}, "Normal",
-		func(title, tagEditString, description string, style ItemStyle, dataType string) {
-			w.AppendItemWithDataType(title, ParseTagEditString(tagEditString), description, style, dataType)
+	w.ShowCreateItemInLaneDialog(nil)
+}
+
+// ShowCreateItemInLaneDialog creates a new item in the given lane, or in the first lane if nil
+func (w *Stage) ShowCreateItemInLaneDialog(lane *model.Lane) {
+	ShowItemDialogWithDataType("New", "", "", "", "", model.DefaultItemStyle, "Normal", time.Time{}, time.Time{},
+		func(title, tagEditString, description, checklistEditString string, style model.ItemStyle, dataType string, start, due time.Time) {
+			w.ConfirmWIPLimit(func() {
+				item := model.NewItem(title, model.ParseTagEditString(tagEditString), description, style, dataType)
+				item.SetDates(start, due)
+				item.SetChecklist(model.ParseChecklist(checklistEditString))
+				if lane != nil {
+					item.LaneID = lane.ID
+				}
+				w.AppendItem(item)
+			})
 		},
 	)
 }
@@ -139,56 +184,160 @@ func (w *Stage) ShowCreateItemDialog() {
 func (w *Stage) ShowEditStageTitleDialog() {
 	ShowEntryDialog("Edit Stage Title", "Title ...", w.Title,
 		func(text string) {
-			w.Title = text
-			w.Refresh()
-			autoSave()
+			w.board.Execute(&model.RenameStageCommand{Stage: w.Stage, Title: text})
+		},
+	)
+}
+
+func (w *Stage) ShowWIPLimitDialog() {
+	text := ""
+	if w.WIPLimit > 0 {
+		text = strconv.Itoa(w.WIPLimit)
+	}
+
+	ShowEntryDialog("Set WIP Limit", "Maximum number of items, empty for no limit ...", text,
+		func(text string) {
+			limit := 0
+			if text = strings.TrimSpace(text); text != "" {
+				var err error
+				if limit, err = strconv.Atoi(text); err != nil || limit < 0 {
+					dialog.ShowError(fmt.Errorf("%q is not a valid WIP limit", text), window)
+					return
+				}
+			}
+			w.board.Execute(&model.SetWIPLimitCommand{Stage: w.Stage, Limit: limit})
 		},
 	)
 }
 
+// ConfirmWIPLimit runs the action adding an item to the stage, after a confirmation if it would exceed the WIP limit
+func (w *Stage) ConfirmWIPLimit(action func()) {
+	if !w.ExceedsWIPLimit(1) {
+		action()
+		return
+	}
+
+	ShowConfirmDialog("WIP Limit", fmt.Sprintf("The stage %q already holds %d of at most %d items.\n\nAdd the item anyway?\n", w.Title, len(w.Items), w.WIPLimit), action)
+}
+
 func (w *Stage) ShowRemoveStageConfirmDialog() {
 	ShowConfirmDialog("Remove Stage", "This will remove the stage and all contained items from the board.\n\nAre you sure?\n",
 		func() {
-			board.RemoveStage(w)
-			autoSave()
+			w.board.RemoveStage(w)
 		},
 	)
 }
 
+// MoveBy moves the stage by the given number of positions to the right, or to the left if negative
+func (w *Stage) MoveBy(step int) bool {
+	index := w.board.StageIndex(w.Stage) + step
+	if index < 0 || index >= len(w.board.Stages) || step == 0 {
+		return false
+	}
+
+	return w.board.MoveStage(w, index)
+}
+
 func (w *Stage) ShowStageMenu() {
+	index := w.board.StageIndex(w.Stage)
+
+	moveLeft := fyne.NewMenuItem("Move Stage Left", func() { w.MoveBy(-1) })
+	moveLeft.Disabled = index <= 0
+	moveRight := fyne.NewMenuItem("Move Stage Right", func() { w.MoveBy(1) })
+	moveRight.Disabled = index >= len(w.board.Stages)-1
+
 	menu := widget.NewPopUpMenu(
 		fyne.NewMenu("Stage",
 			fyne.NewMenuItem("Edit Stage Title", w.ShowEditStageTitleDialog),
+			fyne.NewMenuItem("Set WIP Limit", w.ShowWIPLimitDialog),
+			fyne.NewMenuItem("Sort by Due Date", func() { w.board.Execute(&model.SortByDueCommand{Stages: []*model.Stage{w.Stage}}) }),
+			moveLeft,
+			moveRight,
 			fyne.NewMenuItem("Remove Stage", w.ShowRemoveStageConfirmDialog),
 		), window.Canvas(),
 	)
 	menu.ShowAtPosition(fyne.NewPos(w.Position().X+w.Size().Width-menu.Size().Width-30, w.Position().Y+menu.Size().Height+10))
 }
 
-func (w *Stage) SetFilterTags(filterTags []Tag) {
-	for _, item := range w.Items {
-		item.SetFilterTags(filterTags)
+func (w *Stage) SetFilter(filter *model.Filter) {
+	for _, item := range w.ItemWidgets() {
+		item.SetFilter(filter)
+	}
+}
+
+// ScrollToItem scrolls the item into the middle of the visible area of the stage, if it is not completely visible
+func (w *Stage) ScrollToItem(item *Item) {
+	if w.scrollArea == nil {
+		return
+	}
+
+	/* The item may be nested in a lane section, so its position is taken relative to the scrolled content */
+	itemY := absolutePosition(item).Y - absolutePosition(w.scrollArea.Content).Y
+	offset := w.scrollArea.Offset
+	visibleHeight := w.scrollArea.Size().Height
+	if itemY >= offset.Y && itemY+item.Size().Height <= offset.Y+visibleHeight {
+		return
+	}
+
+	offset.Y = itemY - (visibleHeight-item.Size().Height)/2
+	offset.Y = fyne.Max(0, fyne.Min(offset.Y, w.scrollArea.Content.Size().Height-visibleHeight))
+	w.scrollTo(offset)
+}
+
+// Dragged moves the stage when dragged by its title bar, drags starting below are handled by the items and the scroll area
+func (w *Stage) Dragged(event *fyne.DragEvent) {
+	if activeStageDrag == nil {
+		if w.scrollArea == nil || event.Position.Y >= w.scrollArea.Position().Y {
+			return
+		}
+		startStageDrag(w, event.Position)
 	}
+	updateStageDrag(event.AbsolutePosition)
+}
+
+func (w *Stage) DragEnd() {
+	finishStageDrag()
 }
 
 /* ================================================================================ Public rendering methods */
 func (w *Stage) CreateRenderer() fyne.WidgetRenderer {
 	w.ExtendBaseWidget(w)
 
-	titleLabel := NewCustomLabel(fyne.TextAlignLeading, PaintStyle{color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, false, w.Title, GetScaledTextSubHeadingSize(), fyne.TextStyle{Italic: true}, Paddings{1.0, 1.0, 1.0, 1.0}, Paddings{0.0, 0.0, 0.0, 0.0})
+	titleLabel := NewCustomLabel(fyne.TextAlignLeading, PaintStyle{w.titleColor(), color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 0}, 0}, false, w.titleText(), GetScaledTextSubHeadingSize(), fyne.TextStyle{Italic: true}, Paddings{1.0, 1.0, 1.0, 1.0}, Paddings{0.0, 0.0, 0.0, 0.0})
 	toolbar := widget.NewToolbar(
 		widget.NewToolbarAction(theme.ContentAddIcon(), w.ShowCreateItemDialog),
 		widget.NewToolbarAction(theme.MoreVerticalIcon(), w.ShowStageMenu),
 	)
 
 	itemContainer := container.NewVBox()
-	for _, item := range w.Items {
-		itemContainer.Add(item)
-	}
-
 	scrollArea := container.NewVScroll(itemContainer)
+	scrollArea.OnScrolled = w.scrolled
+	w.scrollArea = scrollArea
+
+	r := &stageRenderer{titleLabel, toolbar, scrollArea, itemContainer, widget.NewSeparator(), widget.NewSeparator(), w}
+	r.syncItemContainer()
+
+	return r
+}
+
+// syncItemContainer fills the scrolled container with the item widgets, or with the lane sections if the board has lanes
+func (r stageRenderer) syncItemContainer() {
+	r.itemContainer.RemoveAll()
+
+	if len(r.w.board.Lanes) > 0 {
+		r.itemContainer.Layout = laneLayout{}
+		for _, section := range r.w.LaneSections() {
+			r.itemContainer.Add(section)
+			section.Refresh()
+		}
+		return
+	}
 
-	return &stageRenderer{titleLabel, toolbar, scrollArea, itemContainer, widget.NewSeparator(), widget.NewSeparator(), w}
+	r.itemContainer.Layout = layout.NewVBoxLayout()
+	for _, item := range r.w.ItemWidgets() {
+		r.itemContainer.Add(item)
+		item.Refresh()
+	}
 }
 
 func (r stageRenderer) Layout(size fyne.Size) {
@@ -226,16 +375,16 @@ func (r stageRenderer) MinSize() fyne.Size {
 }
 
 func (r stageRenderer) Refresh() {
-	r.titleLabel.Text = r.w.Title
-	r.titleLabel.Refresh()
-
-	for _, item := range r.itemContainer.Objects {
-		r.itemContainer.Remove(item)
+	r.titleLabel.Text = r.w.titleText()
+	r.titleLabel.Style.Foreground = r.w.titleColor()
+	r.titleLabel.Style.StrokeWidth = 0
+	if r.w.board.FocusedStage == r.w.Stage && r.w.board.FocusedItem == nil {
+		r.titleLabel.Style.Stroke = ColorToRGBA(theme.Color(theme.ColorNameFocus))
+		r.titleLabel.Style.StrokeWidth = theme.Padding() / 2
 	}
+	r.titleLabel.Refresh()
 
-	for _, item := range r.w.Items {
-		r.itemContainer.Add(item)
-	}
+	r.syncItemContainer()
 	r.scrollArea.Refresh()
 }
 
@@ -20,8 +20,7 @@ type TappableCustomLabel struct {
 func NewTappableCustomLabel(alignment fyne.TextAlign, style PaintStyle, lineWrapping bool, text string, textSize float32, textStyle fyne.TextStyle, paddingMultipliers, textPaddingOffsets Paddings, tapped func()) *TappableCustomLabel {
 	backgroundPaddings, textPaddings := CalculatePaddings(paddingMultipliers, textPaddingOffsets)
 
-	customLabel         := CustomLabel{ Alignment: alignment, Style: style, LineWrapping: lineWrapping, Text: text, TextSize: textSize, TextStyle: textStyle, BackgroundPaddings: backgroundPaddings, TextPaddings: textPaddings }
-	tappableCustomLabel := &TappableCustomLabel{ customLabel, tapped }
+	tappableCustomLabel := &TappableCustomLabel{ CustomLabel{ Alignment: alignment, Style: style, LineWrapping: lineWrapping, Text: text, TextSize: textSize, TextStyle: textStyle, BackgroundPaddings: backgroundPaddings, TextPaddings: textPaddings }, tapped }
 	tappableCustomLabel.ExtendBaseWidget(tappableCustomLabel)
 
 	return tappableCustomLabel
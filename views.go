@@ -0,0 +1,125 @@
+package main
+
+/* This file contains the saved filter views of the board, which are selectable from the dropdown next to the filter entry */
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"strings"
+
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+/* ================================================================================ Private variables */
+var viewSelect *widget.Select
+
+/* ================================================================================ Private functions */
+func newViewSelect() *widget.Select {
+	viewSelect = widget.NewSelect(nil, viewSelected)
+	viewSelect.PlaceHolder = "Views"
+	syncViewSelect()
+
+	return viewSelect
+}
+
+func viewSelected(name string) {
+	view := board.ViewByName(name)
+	if view == nil {
+		return
+	}
+
+	fyne.CurrentApp().Preferences().SetString(filterViewPreference(), view.Name)
+	filterBinding.Set(view.Query)
+}
+
+// syncViewSelect updates the options to the views of the board and selects the view matching the current filter
+func syncViewSelect() {
+	if viewSelect == nil {
+		return
+	}
+
+	options := make([]string, len(board.Views))
+	for i, view := range board.Views {
+		options[i] = view.Name
+	}
+	viewSelect.SetOptions(options)
+
+	view := board.ViewByQuery(board.Filter.Query)
+	if selected := board.ViewByName(viewSelect.Selected); selected != nil && selected.Query == board.Filter.Query {
+		view = selected
+	}
+
+	if view == nil {
+		viewSelect.ClearSelected()
+		if board.Filter.Query != "" {
+			fyne.CurrentApp().Preferences().SetString(filterViewPreference(), "")
+		}
+	} else if view.Name != viewSelect.Selected {
+		viewSelect.SetSelected(view.Name)
+	}
+}
+
+// restoreFilterView activates the view which was last active on the board file, if the board still has it
+func restoreFilterView() {
+	if view := board.ViewByName(fyne.CurrentApp().Preferences().String(filterViewPreference())); view != nil {
+		filterBinding.Set(view.Query)
+	}
+}
+
+// filterViewPreference returns the preference key of the active view, which is remembered per board file
+func filterViewPreference() string {
+	if saveFileURI == nil {
+		return "filterView"
+	}
+	return "filterView:" + saveFileURI.String()
+}
+
+func showSaveViewDialog() {
+	query := board.Filter.Query
+	if strings.TrimSpace(query) == "" {
+		dialog.ShowInformation("Save Filter View", "Enter a filter first, which is then saved as view.", window)
+		return
+	}
+
+	ShowEntryDialog("Save Filter View", "Name ...", viewSelect.Selected,
+		func(name string) {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return
+			}
+
+			save := func() {
+				board.Execute(&model.SaveViewCommand{View: model.FilterView{Name: name, Query: query}})
+				viewSelect.SetSelected(name)
+			}
+
+			if existing := board.ViewByName(name); existing != nil && existing.Query != query {
+				ShowConfirmDialog("Save Filter View", fmt.Sprintf("This will replace the filter of the view %q:\n\n%s\n\nAre you sure?\n", name, existing.Query), save)
+				return
+			}
+			save()
+		},
+	)
+}
+
+func showRemoveViewDialog() {
+	if len(board.Views) < 1 {
+		dialog.ShowInformation("Remove Filter View", "There are no saved views yet.", window)
+		return
+	}
+
+	options := make([]string, len(board.Views))
+	for i, view := range board.Views {
+		options[i] = view.Name
+	}
+
+	ShowSelectDialog("Remove Filter View", "View ...", options,
+		func(index int) {
+			board.Execute(&model.RemoveViewCommand{Name: options[index]})
+		},
+	)
+}
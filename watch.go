@@ -0,0 +1,187 @@
+package main
+
+/* This file contains the detection of changes to the save file made by other programs or users, e.g. in a shared folder */
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bankan/model"
+
+	"fyne.io/fyne/v2"
+)
+
+/* ================================================================================ Constants */
+const (
+	SAVE_FILE_POLL_INTERVAL = 2 * time.Second
+)
+
+/* ================================================================================ Private types */
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+/* ================================================================================ Private variables */
+var saveFileStamp fileStamp
+var ignoredSaveFileStamp fileStamp
+var saveFileBase []byte
+var externalChangeDialogShown bool
+
+/* ================================================================================ Private functions */
+func statSaveFile() (fileStamp, bool) {
+	if saveFileURI == nil || saveFileURI.Scheme() != "file" {
+		return fileStamp{}, false
+	}
+
+	info, err := os.Stat(saveFileURI.Path())
+	if err != nil {
+		return fileStamp{}, false
+	}
+
+	return fileStamp{info.ModTime(), info.Size()}, true
+}
+
+// rememberSaveFileState records the state of the save file after loading or saving it, the data is the base for merging
+func rememberSaveFileState(data []byte) {
+	saveFileStamp, _ = statSaveFile()
+	ignoredSaveFileStamp = fileStamp{}
+	saveFileBase = data
+}
+
+func forgetSaveFileState() {
+	saveFileStamp = fileStamp{}
+	ignoredSaveFileStamp = fileStamp{}
+	saveFileBase = nil
+}
+
+func saveFileChangedExternally() bool {
+	stamp, ok := statSaveFile()
+	return ok && saveFileStamp != (fileStamp{}) && stamp != saveFileStamp
+}
+
+func saveFileChangeIgnored() bool {
+	stamp, _ := statSaveFile()
+	return stamp == ignoredSaveFileStamp
+}
+
+func checkSaveFile() {
+	if saveFileChangedExternally() && !saveFileChangeIgnored() {
+		showExternalChangeDialog()
+	}
+}
+
+func reloadSaveFile() {
+	if err := loadBoardURI(board, saveFileURI); err != nil {
+		showLoadError(saveFileURI, err)
+	}
+}
+
+// mergeSaveFile merges the changes of the save file into the board and ends the external change dialog when done
+func mergeSaveFile() {
+	theirsData, err := os.ReadFile(saveFileURI.Path())
+	theirs := &model.Board{}
+	if err == nil {
+		err = theirs.Load(theirsData)
+	}
+	if err != nil {
+		externalChangeDialogShown = false
+		showLoadError(saveFileURI, err)
+		return
+	}
+
+	base := &model.Board{}
+	if err := base.Load(saveFileBase); err != nil {
+		base = nil
+	}
+
+	merged, conflicts := model.Merge(base, board.Board, theirs)
+	if len(conflicts) < 1 {
+		externalChangeDialogShown = false
+		applyMergedBoard(merged, theirsData)
+		return
+	}
+
+	lines := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		lines[i] = "- " + conflict.String()
+	}
+
+	ShowChoiceDialog("Merge Conflicts",
+		fmt.Sprintf("%d changes conflict with the changes in the file:\n\n%s\n\n", len(conflicts), strings.Join(lines, "\n"))+
+			"Merge Anyway: keep your version of conflicting changes and tag the affected items with \""+model.CONFLICT_TAG+"\"\n"+
+			"Cancel: decide again later",
+		[]string{"Merge Anyway", "Cancel"},
+		func(choice int) {
+			externalChangeDialogShown = false
+
+			if choice == 0 {
+				model.MarkConflicts(merged, conflicts)
+				applyMergedBoard(merged, theirsData)
+			} else {
+				postponeExternalChange()
+			}
+		},
+	)
+}
+
+func applyMergedBoard(merged *model.Board, theirsData []byte) {
+	/* Accept the file as new base, so the merged board may replace it */
+	rememberSaveFileState(theirsData)
+
+	board.Execute(&model.ReplaceBoardCommand{Board: merged, Action: model.ActivityMerged})
+}
+
+func overwriteSaveFile() {
+	saveFileStamp, _ = statSaveFile()
+	saveBoard(saveFileURI, true)
+}
+
+func postponeExternalChange() {
+	ignoredSaveFileStamp, _ = statSaveFile()
+	setSaveStatus(SAVE_STATUS_UNSAVED)
+}
+
+func showExternalChangeDialog() {
+	if externalChangeDialogShown {
+		return
+	}
+	externalChangeDialogShown = true
+
+	ShowChoiceDialog("Board File Changed",
+		"The board file\n"+saveFileURI.Path()+"\nwas changed by another program or user.\n\n"+
+			"Reload: discard your unsaved changes and load the file\n"+
+			"Merge: combine the changes on both sides\n"+
+			"Overwrite: replace the file by your version\n"+
+			"Later: keep your version unsaved for now",
+		[]string{"Reload", "Merge", "Overwrite", "Later"},
+		func(choice int) {
+			/* The conflicts of a merge are still part of the dialog, so the watcher does not ask again meanwhile */
+			if choice != 1 {
+				externalChangeDialogShown = false
+			}
+
+			switch choice {
+			case 0:
+				reloadSaveFile()
+			case 1:
+				mergeSaveFile()
+			case 2:
+				overwriteSaveFile()
+			default:
+				postponeExternalChange()
+			}
+		},
+	)
+}
+
+func startSaveFileWatcher() {
+	go func() {
+		for range time.Tick(SAVE_FILE_POLL_INTERVAL) {
+			fyne.Do(checkSaveFile)
+		}
+	}()
+}